@@ -0,0 +1,402 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	networkingv1 "istio.io/client-go/pkg/apis/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"msa-sample-01/pkg/platform"
+)
+
+var (
+	errIstioUnavailable = errors.New("istio client not available")
+	errUnknownService   = errors.New("unknown service")
+	errRolloutConflict  = errors.New("rollout conflict")
+)
+
+// WeightUpdateRequest is the body of PUT /admin/traffic-weights/{service}
+type WeightUpdateRequest struct {
+	Weights         map[string]int `json:"weights"`
+	ResourceVersion string         `json:"resourceVersion,omitempty"`
+}
+
+// ServiceWeights describes the weighted route of one VirtualService
+type ServiceWeights struct {
+	Service         string         `json:"service"`
+	VirtualService  string         `json:"virtualService"`
+	Weights         map[string]int `json:"weights"`
+	ResourceVersion string         `json:"resourceVersion"`
+}
+
+// AuditRecord captures one change made through the admin API
+type AuditRecord struct {
+	Time     string      `json:"time"`
+	Actor    string      `json:"actor"`
+	Remote   string      `json:"remote"`
+	Action   string      `json:"action"`
+	Target   string      `json:"target"`
+	Before   interface{} `json:"before,omitempty"`
+	After    interface{} `json:"after,omitempty"`
+	Result   string      `json:"result"`
+	ErrorMsg string      `json:"error,omitempty"`
+}
+
+// auditLog keeps the most recent admin changes in memory
+type auditLog struct {
+	mu      sync.Mutex
+	records []AuditRecord
+	limit   int
+}
+
+var audit = &auditLog{limit: getEnvInt("AUDIT_LOG_SIZE", 500)}
+
+func (a *auditLog) add(record AuditRecord) {
+	record.Time = time.Now().Format(time.RFC3339)
+	slog.Info("AUDIT", "actor", record.Actor, "action", record.Action, "target", record.Target, "result", record.Result, "error", record.ErrorMsg)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(a.records, record)
+	if len(a.records) > a.limit {
+		a.records = a.records[len(a.records)-a.limit:]
+	}
+}
+
+func (a *auditLog) list() []AuditRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	records := make([]AuditRecord, len(a.records))
+	copy(records, a.records)
+	return records
+}
+
+// adminActor identifies the caller for audit records
+func adminActor(r *http.Request) string {
+	if user := r.Header.Get("X-Admin-User"); user != "" {
+		return user
+	}
+	return "admin"
+}
+
+// authorizeAdmin checks "Authorization: Bearer <ADMIN_TOKEN>"; the admin API is disabled without ADMIN_TOKEN
+func authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	return platform.AuthorizeBearer(w, r, os.Getenv("ADMIN_TOKEN"), "api-gateway-admin")
+}
+
+// adminHandler dispatches authenticated /admin/ requests
+func adminHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	withAdminValidation(routeAdmin)(w, r)
+}
+
+// routeAdmin dispatches /admin/ requests that passed authorization and validation
+func routeAdmin(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/admin/traffic-weights/"):
+		service := strings.TrimPrefix(r.URL.Path, "/admin/traffic-weights/")
+		switch r.Method {
+		case http.MethodGet:
+			getServiceWeightsHandler(w, r, service)
+		case http.MethodPut:
+			updateServiceWeightsHandler(w, r, service)
+		default:
+			platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
+		}
+	case strings.HasPrefix(r.URL.Path, "/admin/rollouts"):
+		rolloutsHandler(w, r)
+	case strings.HasPrefix(r.URL.Path, "/admin/scenarios"):
+		scenariosHandler(w, r)
+	case strings.HasPrefix(r.URL.Path, "/admin/chaos"):
+		chaosHandler(w, r)
+	case strings.HasPrefix(r.URL.Path, "/admin/faults/"):
+		faultsProxyHandler(w, r)
+	case r.URL.Path == "/admin/audit":
+		json.NewEncoder(w).Encode(audit.list())
+	default:
+		platform.Error(w, r, http.StatusNotFound, platform.CodeNotFound, "Unknown admin endpoint")
+	}
+}
+
+// faultsProxyHandler forwards /admin/faults/{service}/... to the service's own fault API
+func faultsProxyHandler(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/admin/faults/")
+	service, subPath, _ := strings.Cut(rest, "/")
+	service = normalizeServiceName(service)
+
+	route, ok := gatewayConfig.route(service)
+	if !ok {
+		platform.Error(w, r, http.StatusNotFound, codeUnknownService, fmt.Sprintf("%v %q", errUnknownService, service))
+		return
+	}
+	targetURL, err := url.Parse(route.Upstream)
+	if err != nil {
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeInternal, err.Error())
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		audit.add(AuditRecord{
+			Actor:  adminActor(r),
+			Remote: r.RemoteAddr,
+			Action: "faults-" + strings.ToLower(r.Method),
+			Target: strings.TrimSuffix(service+"/"+subPath, "/"),
+			Result: "forwarded",
+		})
+	}
+
+	r.URL.Path = strings.TrimSuffix("/admin/faults/"+subPath, "/")
+	// Authorization 은 그대로 전달: 서비스의 장애 주입 API 도 같은 ADMIN_TOKEN 을 검사
+	// adminHandler 가 설정한 값이 남으면 서비스의 Content-Type 뒤에 추가되어 problem+json 이 가려짐
+	w.Header().Del("Content-Type")
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	proxy.Transport = tracedTransport
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		platform.WriteProblem(w, upstreamProblem(r, service, err))
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// normalizeServiceName accepts both "user" and "user-service"; configured service names are kept as is
+func normalizeServiceName(service string) string {
+	service = strings.Trim(service, "/")
+	if _, ok := gatewayConfig.route(service); !ok && !strings.HasSuffix(service, "-service") {
+		service += "-service"
+	}
+	return service
+}
+
+// weightedRoute returns the non-canary HTTP route holding the cluster weights
+func weightedRoute(vs *networkingv1.VirtualService) (*networkingv1alpha3.HTTPRoute, error) {
+	for _, route := range vs.Spec.Http {
+		if len(route.Match) == 0 && len(route.Route) > 0 {
+			return route, nil
+		}
+	}
+	return nil, fmt.Errorf("virtualservice %s has no unconditional weighted route", vs.Name)
+}
+
+func routeWeights(route *networkingv1alpha3.HTTPRoute) map[string]int {
+	weights := map[string]int{}
+	for _, destination := range route.Route {
+		if destination.Destination != nil {
+			weights[destination.Destination.Subset] = int(destination.Weight)
+		}
+	}
+	return weights
+}
+
+// getServiceVirtualService loads the VirtualService of a service
+func getServiceVirtualService(ctx context.Context, service string) (*networkingv1.VirtualService, error) {
+	if istioClient == nil {
+		return nil, errIstioUnavailable
+	}
+	route, ok := gatewayConfig.route(service)
+	if !ok || route.VirtualService == "" {
+		return nil, fmt.Errorf("%w %q", errUnknownService, service)
+	}
+	return istioClient.NetworkingV1().VirtualServices(gatewayConfig.Namespace).Get(ctx, route.VirtualService, metav1.GetOptions{})
+}
+
+// getServiceWeightsHandler returns the weights and resourceVersion needed for an update
+func getServiceWeightsHandler(w http.ResponseWriter, r *http.Request, service string) {
+	service = normalizeServiceName(service)
+	vs, err := getServiceVirtualService(r.Context(), service)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	route, err := weightedRoute(vs)
+	if err != nil {
+		platform.Error(w, r, http.StatusConflict, codeConflict, err.Error())
+		return
+	}
+
+	json.NewEncoder(w).Encode(ServiceWeights{
+		Service:         service,
+		VirtualService:  vs.Name,
+		Weights:         routeWeights(route),
+		ResourceVersion: vs.ResourceVersion,
+	})
+}
+
+// updateServiceWeightsHandler validates and applies new weights to the service's VirtualService
+func updateServiceWeightsHandler(w http.ResponseWriter, r *http.Request, service string) {
+	service = normalizeServiceName(service)
+
+	var req WeightUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if ifMatch := strings.Trim(r.Header.Get("If-Match"), `"`); ifMatch != "" && req.ResourceVersion == "" {
+		req.ResourceVersion = ifMatch
+	}
+
+	record := AuditRecord{
+		Actor:  adminActor(r),
+		Remote: r.RemoteAddr,
+		Action: "update-traffic-weights",
+		Target: service,
+		After:  req.Weights,
+	}
+
+	// 롤아웃 중인 서비스는 컨트롤러가 가중치를 소유
+	err := checkNoActiveRollout(service)
+	var updated, before ServiceWeights
+	if err == nil {
+		updated, before, err = applyServiceWeights(r.Context(), service, req.Weights, req.ResourceVersion)
+	}
+	if err != nil {
+		record.Result = "failed"
+		record.ErrorMsg = err.Error()
+		record.Before = before
+		audit.add(record)
+		errorResponse(w, r, err)
+		return
+	}
+
+	record.Result = "success"
+	record.Before = before
+	audit.add(record)
+
+	// weights 이벤트는 watchTrafficWeights가 변경을 감지해 발행
+	json.NewEncoder(w).Encode(updated)
+}
+
+// weightValidationError marks invalid weight input
+type weightValidationError struct{ msg string }
+
+func (e weightValidationError) Error() string { return e.msg }
+
+// applyServiceWeights writes new subset weights; a non-empty resourceVersion enforces optimistic concurrency
+func applyServiceWeights(ctx context.Context, service string, weights map[string]int, resourceVersion string) (ServiceWeights, ServiceWeights, error) {
+	var before ServiceWeights
+
+	total := 0
+	for subset, weight := range weights {
+		if weight < 0 || weight > 100 {
+			return ServiceWeights{}, before, weightValidationError{fmt.Sprintf("weight for %s must be between 0 and 100", subset)}
+		}
+		total += weight
+	}
+	if total != 100 {
+		return ServiceWeights{}, before, weightValidationError{fmt.Sprintf("weights must sum to 100, got %d", total)}
+	}
+
+	vs, err := getServiceVirtualService(ctx, service)
+	if err != nil {
+		return ServiceWeights{}, before, err
+	}
+	route, err := weightedRoute(vs)
+	if err != nil {
+		return ServiceWeights{}, before, err
+	}
+
+	before = ServiceWeights{
+		Service:         service,
+		VirtualService:  vs.Name,
+		Weights:         routeWeights(route),
+		ResourceVersion: vs.ResourceVersion,
+	}
+
+	// 요청한 subset이 모두 라우트에 존재하고, 라우트의 subset이 모두 요청에 포함되는지 확인 (누락된 subset이 0으로 바뀌지 않도록)
+	for subset := range weights {
+		if _, ok := before.Weights[subset]; !ok {
+			return ServiceWeights{}, before, weightValidationError{fmt.Sprintf("subset %q is not routed by %s", subset, vs.Name)}
+		}
+	}
+	for _, destination := range route.Route {
+		if destination.Destination == nil {
+			continue
+		}
+		if _, ok := weights[destination.Destination.Subset]; !ok {
+			return ServiceWeights{}, before, weightValidationError{fmt.Sprintf("weights must list every subset routed by %s, missing %q", vs.Name, destination.Destination.Subset)}
+		}
+	}
+
+	for _, destination := range route.Route {
+		if destination.Destination != nil {
+			destination.Weight = int32(weights[destination.Destination.Subset])
+		}
+	}
+	if resourceVersion != "" {
+		vs.ResourceVersion = resourceVersion
+	}
+
+	result, err := istioClient.NetworkingV1().VirtualServices(gatewayConfig.Namespace).Update(ctx, vs, metav1.UpdateOptions{})
+	if err != nil {
+		return ServiceWeights{}, before, err
+	}
+
+	resultRoute, err := weightedRoute(result)
+	if err != nil {
+		return ServiceWeights{}, before, err
+	}
+	return ServiceWeights{
+		Service:         service,
+		VirtualService:  result.Name,
+		Weights:         routeWeights(resultRoute),
+		ResourceVersion: result.ResourceVersion,
+	}, before, nil
+}
+
+// statusForError maps admin errors to HTTP status codes
+func statusForError(err error) int {
+	var validation weightValidationError
+	switch {
+	case errors.As(err, &validation), errors.Is(err, errAmbiguousScenario):
+		return http.StatusBadRequest
+	case apierrors.IsConflict(err), errors.Is(err, errRolloutConflict), errors.Is(err, errChaosConflict):
+		return http.StatusConflict
+	case apierrors.IsNotFound(err), errors.Is(err, errUnknownService), errors.Is(err, errUnknownScenario):
+		return http.StatusNotFound
+	case apierrors.IsForbidden(err):
+		return http.StatusForbidden
+	case errors.Is(err, errIstioUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// codeForError maps admin errors to problem codes, matching statusForError
+func codeForError(err error) string {
+	var validation weightValidationError
+	switch {
+	case errors.As(err, &validation):
+		return codeInvalidWeights
+	case errors.Is(err, errRolloutConflict):
+		return codeRolloutConflict
+	case apierrors.IsConflict(err), errors.Is(err, errChaosConflict):
+		return codeConflict
+	case errors.Is(err, errUnknownService):
+		return codeUnknownService
+	case errors.Is(err, errUnknownScenario):
+		return codeUnknownScenario
+	case errors.Is(err, errAmbiguousScenario):
+		return codeAmbiguousScenario
+	case apierrors.IsNotFound(err):
+		return platform.CodeNotFound
+	case apierrors.IsForbidden(err):
+		return codeForbidden
+	case errors.Is(err, errIstioUnavailable):
+		return codeIstioUnavailable
+	}
+	return platform.CodeInternal
+}
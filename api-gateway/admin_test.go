@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	networkingv1 "istio.io/client-go/pkg/apis/networking/v1"
+	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// testVirtualService routes the service 50/50 to ctx1/ctx2 behind a header-matched canary route
+func testVirtualService(name, service string) *networkingv1.VirtualService {
+	destination := func(subset string, weight int32) *networkingv1alpha3.HTTPRouteDestination {
+		return &networkingv1alpha3.HTTPRouteDestination{
+			Destination: &networkingv1alpha3.Destination{Host: service, Subset: subset},
+			Weight:      weight,
+		}
+	}
+	return &networkingv1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "theater-msa", ResourceVersion: "1"},
+		Spec: networkingv1alpha3.VirtualService{
+			Hosts: []string{service},
+			Http: []*networkingv1alpha3.HTTPRoute{
+				{
+					Match: []*networkingv1alpha3.HTTPMatchRequest{{Headers: map[string]*networkingv1alpha3.StringMatch{
+						"x-canary": {MatchType: &networkingv1alpha3.StringMatch_Exact{Exact: "true"}},
+					}}},
+					Route: []*networkingv1alpha3.HTTPRouteDestination{destination("ctx2", 100)},
+				},
+				{Route: []*networkingv1alpha3.HTTPRouteDestination{destination("ctx1", 50), destination("ctx2", 50)}},
+			},
+		},
+	}
+}
+
+// useFakeIstio replaces the Istio client and the gateway config for one test
+func useFakeIstio(t *testing.T) {
+	t.Helper()
+	previousClient, previousConfig := istioClient, gatewayConfig
+	istioClient = istiofake.NewSimpleClientset(
+		testVirtualService("user-service-vs", "user-service"),
+		testVirtualService("movie-service-vs", "movie-service"),
+	)
+	gatewayConfig = defaultGatewayConfig()
+	t.Cleanup(func() {
+		istioClient, gatewayConfig = previousClient, previousConfig
+	})
+}
+
+func TestApplyServiceWeights(t *testing.T) {
+	tests := []struct {
+		name        string
+		service     string
+		weights     map[string]int
+		wantWeights map[string]int
+		wantErr     error
+	}{
+		{name: "full map", service: "movie-service", weights: map[string]int{"ctx1": 20, "ctx2": 80}, wantWeights: map[string]int{"ctx1": 20, "ctx2": 80}},
+		{name: "all to one subset", service: "movie-service", weights: map[string]int{"ctx1": 0, "ctx2": 100}, wantWeights: map[string]int{"ctx1": 0, "ctx2": 100}},
+		{name: "partial map", service: "movie-service", weights: map[string]int{"ctx2": 100}, wantErr: weightValidationError{}},
+		{name: "sum below 100", service: "movie-service", weights: map[string]int{"ctx1": 20, "ctx2": 70}, wantErr: weightValidationError{}},
+		{name: "negative weight", service: "movie-service", weights: map[string]int{"ctx1": -10, "ctx2": 110}, wantErr: weightValidationError{}},
+		{name: "unknown subset", service: "movie-service", weights: map[string]int{"ctx1": 50, "ctx3": 50}, wantErr: weightValidationError{}},
+		{name: "unknown service", service: "ticket-service", weights: map[string]int{"ctx1": 50, "ctx2": 50}, wantErr: errUnknownService},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeIstio(t)
+			updated, before, err := applyServiceWeights(context.Background(), tt.service, tt.weights, "")
+			if tt.wantErr != nil {
+				var validation weightValidationError
+				if _, ok := tt.wantErr.(weightValidationError); ok && !errors.As(err, &validation) {
+					t.Fatalf("error = %v, want a weightValidationError", err)
+				} else if !ok && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+
+				// 거부된 요청은 VirtualService 를 바꾸지 않음
+				vs, getErr := getServiceVirtualService(context.Background(), "movie-service")
+				if getErr != nil {
+					t.Fatal(getErr)
+				}
+				route, _ := weightedRoute(vs)
+				if got := routeWeights(route); !reflect.DeepEqual(got, map[string]int{"ctx1": 50, "ctx2": 50}) {
+					t.Errorf("weights after rejected update = %v, want unchanged 50/50", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyServiceWeights() error = %v", err)
+			}
+			if !reflect.DeepEqual(updated.Weights, tt.wantWeights) {
+				t.Errorf("updated weights = %v, want %v", updated.Weights, tt.wantWeights)
+			}
+			if !reflect.DeepEqual(before.Weights, map[string]int{"ctx1": 50, "ctx2": 50}) {
+				t.Errorf("before weights = %v, want 50/50", before.Weights)
+			}
+
+			// 헤더 매치 라우트는 그대로 유지
+			vs, err := getServiceVirtualService(context.Background(), tt.service)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := vs.Spec.Http[0].Route[0].Weight; got != 100 {
+				t.Errorf("canary route weight = %d, want 100", got)
+			}
+		})
+	}
+}
+
+func TestApplyServiceWeightsWithoutIstio(t *testing.T) {
+	previous := istioClient
+	istioClient = nil
+	t.Cleanup(func() { istioClient = previous })
+
+	_, _, err := applyServiceWeights(context.Background(), "movie-service", map[string]int{"ctx1": 50, "ctx2": 50}, "")
+	if !errors.Is(err, errIstioUnavailable) {
+		t.Errorf("error = %v, want %v", err, errIstioUnavailable)
+	}
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+		wantCode      string
+	}{
+		{name: "valid token", token: "secret", authorization: "Bearer secret", wantStatus: http.StatusOK},
+		{name: "missing header", token: "secret", wantStatus: http.StatusUnauthorized, wantCode: platform.CodeUnauthorized},
+		{name: "wrong token", token: "secret", authorization: "Bearer other", wantStatus: http.StatusUnauthorized, wantCode: platform.CodeUnauthorized},
+		{name: "token without bearer prefix", token: "secret", authorization: "secret", wantStatus: http.StatusUnauthorized, wantCode: platform.CodeUnauthorized},
+		{name: "lowercase scheme only", token: "secret", authorization: "bearer", wantStatus: http.StatusUnauthorized, wantCode: platform.CodeUnauthorized},
+		{name: "admin api disabled", authorization: "Bearer secret", wantStatus: http.StatusServiceUnavailable, wantCode: platform.CodeAdminDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADMIN_TOKEN", tt.token)
+			r := httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			adminHandler(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != platform.ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", got, platform.ProblemContentType)
+			}
+			var problem platform.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUpdateServiceWeightsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "update", path: "/admin/traffic-weights/movie", body: `{"weights":{"ctx1":30,"ctx2":70}}`, wantStatus: http.StatusOK},
+		{name: "partial map", path: "/admin/traffic-weights/movie-service", body: `{"weights":{"ctx2":100}}`, wantStatus: http.StatusBadRequest, wantCode: codeInvalidWeights},
+		{name: "invalid body", path: "/admin/traffic-weights/movie-service", body: `{"weights":`, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest},
+		{name: "unknown service", path: "/admin/traffic-weights/ticket", body: `{"weights":{"ctx1":50,"ctx2":50}}`, wantStatus: http.StatusNotFound, wantCode: codeUnknownService},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeIstio(t)
+			t.Setenv("ADMIN_TOKEN", "secret")
+			r := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			r.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			adminHandler(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				var updated ServiceWeights
+				if err := json.NewDecoder(w.Body).Decode(&updated); err != nil {
+					t.Fatalf("decode weights: %v", err)
+				}
+				if updated.Service != "movie-service" || !reflect.DeepEqual(updated.Weights, map[string]int{"ctx1": 30, "ctx2": 70}) {
+					t.Errorf("updated = %+v, want movie-service 30/70", updated)
+				}
+				return
+			}
+			var problem platform.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,693 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"msa-sample-01/api-gateway/loadgen"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// EventTypeChaos is published whenever a chaos experiment changes state
+const EventTypeChaos = "chaos"
+
+// chaosFaultTimeout bounds fault injection and rollback; rollback runs even after the experiment was aborted
+const chaosFaultTimeout = 30 * time.Second
+
+var (
+	errChaosConflict = errors.New("chaos experiment conflict")
+	errFaultNotFound = errors.New("fault rule not found")
+)
+
+// Chaos experiment states
+const (
+	ChaosScheduled = "scheduled"
+	ChaosRunning   = "running"
+	ChaosPassed    = "passed"
+	ChaosFailed    = "failed"
+	ChaosAborted   = "aborted"
+	ChaosError     = "error"
+)
+
+// Hypothesis metrics
+const (
+	MetricErrorRate = "errorRate"
+	MetricP95Ms     = "p95Ms"
+)
+
+// ChaosFault declares the fault to inject: a practice scenario through Istio,
+// or an in-service fault rule forwarded to the service's /admin/faults API
+type ChaosFault struct {
+	Scenario string          `json:"scenario,omitempty"`
+	Service  string          `json:"service,omitempty"`
+	Rule     json.RawMessage `json:"rule,omitempty"`
+}
+
+// ChaosLoad describes the load generated against the gateway while the fault is active
+type ChaosLoad struct {
+	RatePerSecond int      `json:"ratePerSecond"`
+	Paths         []string `json:"paths"`
+}
+
+// ChaosHypothesis is a steady-state condition that must hold during the experiment
+type ChaosHypothesis struct {
+	Service string  `json:"service,omitempty"` // 비어 있으면 게이트웨이 전체
+	Metric  string  `json:"metric"`
+	Max     float64 `json:"max"`
+}
+
+// ChaosRollback restores the system after the experiment; the default undoes the fault
+type ChaosRollback struct {
+	Scenario string `json:"scenario,omitempty"`
+}
+
+// ChaosExperimentRequest is the body of POST /admin/chaos
+type ChaosExperimentRequest struct {
+	Name            string            `json:"name"`
+	Fault           ChaosFault        `json:"fault"`
+	DurationSeconds int               `json:"durationSeconds"`
+	StartAt         string            `json:"startAt,omitempty"` // RFC3339, 비어 있으면 즉시 실행
+	Load            ChaosLoad         `json:"load"`
+	Hypotheses      []ChaosHypothesis `json:"hypotheses"`
+	Rollback        ChaosRollback     `json:"rollback"`
+}
+
+// HypothesisResult is the verdict of one hypothesis
+type HypothesisResult struct {
+	ChaosHypothesis
+	Observed float64 `json:"observed"`
+	Samples  int     `json:"samples"`
+	Passed   bool    `json:"passed"`
+}
+
+// LoadSummary counts the requests sent by the experiment's load generator
+type LoadSummary struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"` // 연결 실패 등 응답을 받지 못한 요청
+}
+
+// ChaosExperiment is the state and report of one experiment
+type ChaosExperiment struct {
+	ID          string                 `json:"id"`
+	Request     ChaosExperimentRequest `json:"request"`
+	Status      string                 `json:"status"`
+	Message     string                 `json:"message,omitempty"`
+	CreatedBy   string                 `json:"createdBy"`
+	CreatedAt   string                 `json:"createdAt"`
+	StartedAt   string                 `json:"startedAt,omitempty"`
+	FinishedAt  string                 `json:"finishedAt,omitempty"`
+	Load        LoadSummary            `json:"load"`
+	Hypotheses  []HypothesisResult     `json:"hypotheses"`
+	Stats       []ServiceClusterStats  `json:"stats"`
+	RollbackErr string                 `json:"rollbackError,omitempty"`
+}
+
+// experiment couples the experiment state with the cancel function of its goroutine
+type experiment struct {
+	mu     sync.Mutex
+	state  ChaosExperiment
+	cancel context.CancelFunc
+}
+
+// chaosRunner owns the experiments started by this gateway instance
+type chaosRunner struct {
+	mu          sync.Mutex
+	experiments map[string]*experiment
+}
+
+var chaos = &chaosRunner{experiments: map[string]*experiment{}}
+
+// chaosLoadTarget is where the load generator sends requests; going through the gateway records statistics
+func chaosLoadTarget() string {
+	return getEnvString("CHAOS_LOAD_TARGET", fmt.Sprintf("http://localhost:%d", gatewayConfig.Port))
+}
+
+func (e *experiment) snapshot() ChaosExperiment {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	state := e.state
+	state.Hypotheses = append([]HypothesisResult{}, e.state.Hypotheses...)
+	state.Stats = append([]ServiceClusterStats{}, e.state.Stats...)
+	return state
+}
+
+// update mutates the state under lock and publishes the new snapshot
+func (e *experiment) update(mutate func(state *ChaosExperiment)) {
+	e.mu.Lock()
+	mutate(&e.state)
+	e.mu.Unlock()
+	events.publish(EventTypeChaos, e.snapshot())
+}
+
+// validateChaosRequest fills defaults and rejects inconsistent experiments
+func validateChaosRequest(req *ChaosExperimentRequest) error {
+	switch {
+	case req.Fault.Scenario != "" && req.Fault.Service != "":
+		return weightValidationError{"fault must set either scenario or service, not both"}
+	case req.Fault.Scenario == "" && req.Fault.Service == "":
+		return weightValidationError{"fault must set scenario or service"}
+	case req.Fault.Service != "":
+		req.Fault.Service = normalizeServiceName(req.Fault.Service)
+		if _, ok := gatewayConfig.route(req.Fault.Service); !ok {
+			return fmt.Errorf("%w %q", errUnknownService, req.Fault.Service)
+		}
+		if len(req.Fault.Rule) == 0 {
+			return weightValidationError{"fault.rule is required for in-service faults"}
+		}
+		var rule map[string]json.RawMessage
+		if err := json.Unmarshal(req.Fault.Rule, &rule); err != nil {
+			return weightValidationError{"fault.rule must be a JSON object"}
+		}
+		if _, ok := rule["id"]; ok {
+			return weightValidationError{"fault.rule.id is set to the experiment id"}
+		}
+	}
+
+	if req.DurationSeconds <= 0 {
+		req.DurationSeconds = 60
+	}
+	if req.StartAt != "" {
+		if _, err := time.Parse(time.RFC3339, req.StartAt); err != nil {
+			return weightValidationError{"startAt must be an RFC3339 timestamp"}
+		}
+	}
+	if req.Load.RatePerSecond < 0 || req.Load.RatePerSecond > 200 {
+		return weightValidationError{"load.ratePerSecond must be between 0 and 200"}
+	}
+	if req.Load.RatePerSecond == 0 {
+		req.Load.RatePerSecond = 10
+	}
+	if len(req.Load.Paths) == 0 {
+		req.Load.Paths = gatewayConfig.pathPrefixes()
+	}
+
+	if len(req.Hypotheses) == 0 {
+		req.Hypotheses = []ChaosHypothesis{
+			{Metric: MetricErrorRate, Max: 0.01},
+			{Metric: MetricP95Ms, Max: 500},
+		}
+	}
+	for i, hypothesis := range req.Hypotheses {
+		if hypothesis.Metric != MetricErrorRate && hypothesis.Metric != MetricP95Ms {
+			return weightValidationError{fmt.Sprintf("unknown hypothesis metric %q", hypothesis.Metric)}
+		}
+		if hypothesis.Service != "" {
+			req.Hypotheses[i].Service = normalizeServiceName(hypothesis.Service)
+		}
+	}
+
+	if req.Name == "" {
+		req.Name = req.Fault.Scenario
+		if req.Name == "" {
+			req.Name = req.Fault.Service + " fault"
+		}
+	}
+	return nil
+}
+
+// start registers an experiment and launches its goroutine; an experiment whose fault overlaps
+// a scheduled or running one is rejected, since its rollback would undo the other's fault
+func (c *chaosRunner) start(req ChaosExperimentRequest, actor string) (*experiment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 롤아웃의 SLO 판정을 흐리고 롤백이 롤아웃 가중치를 덮어쓰므로 거부 (잠금 순서: chaos → rollouts)
+	if err := checkNoActiveRollout(req.services()...); err != nil {
+		return nil, err
+	}
+	for _, existing := range c.experiments {
+		state := existing.snapshot()
+		if state.Status != ChaosScheduled && state.Status != ChaosRunning {
+			continue
+		}
+		// 시나리오는 모든 서비스의 VirtualService/DestinationRule 을 바꾸므로 다른 실험과 겹침
+		other := state.Request.Fault
+		if req.Fault.Scenario != "" || other.Scenario != "" || other.Service == req.Fault.Service {
+			return nil, fmt.Errorf("%w: experiment %s is %s for %s", errChaosConflict, state.ID, state.Status, other.Scenario+other.Service)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &experiment{
+		cancel: cancel,
+		state: ChaosExperiment{
+			ID:         newID("chaos"),
+			Request:    req,
+			Status:     ChaosScheduled,
+			CreatedBy:  actor,
+			CreatedAt:  time.Now().Format(time.RFC3339),
+			Hypotheses: []HypothesisResult{},
+			Stats:      []ServiceClusterStats{},
+		},
+	}
+	c.experiments[e.state.ID] = e
+
+	go e.run(ctx)
+	return e, nil
+}
+
+// services lists the services whose faults or routing the experiment changes; scenarios replace
+// the VirtualServices of every weighted service
+func (req ChaosExperimentRequest) services() []string {
+	services := []string{}
+	if req.Fault.Scenario != "" || req.Rollback.Scenario != "" {
+		services = gatewayConfig.weightedServices()
+	}
+	if req.Fault.Service != "" && !slices.Contains(services, req.Fault.Service) {
+		services = append(services, req.Fault.Service)
+	}
+	return services
+}
+
+// activeFor describes the scheduled or running experiment that changes service
+func (c *chaosRunner) activeFor(service string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, existing := range c.experiments {
+		state := existing.snapshot()
+		if (state.Status == ChaosScheduled || state.Status == ChaosRunning) && slices.Contains(state.Request.services(), service) {
+			return fmt.Sprintf("experiment %s is %s for %s", state.ID, state.Status, service), true
+		}
+	}
+	return "", false
+}
+
+func (c *chaosRunner) get(id string) (*experiment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.experiments[id]
+	return e, ok
+}
+
+func (c *chaosRunner) list() []ChaosExperiment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := make([]ChaosExperiment, 0, len(c.experiments))
+	for _, e := range c.experiments {
+		list = append(list, e.snapshot())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt < list[j].CreatedAt })
+	return list
+}
+
+// run waits for the scheduled time, injects the fault under load, verifies the hypotheses and rolls back
+func (e *experiment) run(ctx context.Context) {
+	req := e.snapshot().Request
+
+	if req.StartAt != "" {
+		startAt, _ := time.Parse(time.RFC3339, req.StartAt)
+		select {
+		case <-time.After(time.Until(startAt)):
+		case <-ctx.Done():
+			e.finish(ChaosAborted, "aborted before start")
+			return
+		}
+	}
+
+	// 가설은 장애 주입 이후 구간의 기록만으로 평가
+	started := time.Now()
+	e.update(func(s *ChaosExperiment) {
+		s.Status = ChaosRunning
+		s.StartedAt = started.Format(time.RFC3339)
+	})
+
+	undo, err := e.injectFault(ctx)
+	if err != nil {
+		e.finish(ChaosError, fmt.Sprintf("failed to inject fault: %v", err))
+		return
+	}
+
+	targets := make([]loadgen.Target, 0, len(req.Load.Paths))
+	for _, path := range req.Load.Paths {
+		targets = append(targets, loadgen.Target{Name: path, Path: path, Weight: 1})
+	}
+	report, err := loadgen.Run(ctx, loadgen.Config{
+		BaseURL:     chaosLoadTarget(),
+		Targets:     targets,
+		Rate:        float64(req.Load.RatePerSecond),
+		Concurrency: 50,
+		Duration:    time.Duration(req.DurationSeconds) * time.Second,
+	})
+	if err != nil {
+		slog.Error("Chaos experiment load generation failed", "error", err)
+		report = &loadgen.Report{}
+	}
+
+	records := traffic.since(started)
+	hypotheses, passed := evaluateHypotheses(req.Hypotheses, records)
+	e.update(func(s *ChaosExperiment) {
+		s.Load = LoadSummary{Sent: report.Requests, Failed: report.Failures}
+		s.Hypotheses = hypotheses
+		s.Stats = aggregate(records)
+	})
+
+	rollbackErr := e.rollback(undo)
+	if rollbackErr != nil {
+		e.update(func(s *ChaosExperiment) { s.RollbackErr = rollbackErr.Error() })
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		e.finish(ChaosAborted, "aborted by operator")
+	case rollbackErr != nil:
+		e.finish(ChaosError, "rollback failed")
+	case passed:
+		e.finish(ChaosPassed, "all steady-state hypotheses held")
+	default:
+		e.finish(ChaosFailed, "steady-state hypothesis violated")
+	}
+}
+
+// injectFault applies the fault and returns the function that undoes it
+func (e *experiment) injectFault(ctx context.Context) (func() error, error) {
+	req := e.snapshot().Request
+
+	if req.Fault.Scenario != "" {
+		_, err := scenarios.apply(ctx, req.Fault.Scenario)
+		e.audit("chaos-fault", req.Fault.Scenario, err)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), chaosFaultTimeout)
+			defer cancel()
+			_, err := scenarios.apply(ctx, initialScenario)
+			return err
+		}, nil
+	}
+
+	// 서비스를 거치면 임의의 파드 하나에만 전달되므로 파드마다 실험 ID 로 주입하고 같은 파드에서 삭제
+	var rule struct {
+		Match struct {
+			Clusters []string `json:"clusters"`
+		} `json:"match"`
+	}
+	if err := json.Unmarshal(req.Fault.Rule, &rule); err != nil {
+		return nil, fmt.Errorf("invalid fault.rule: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, chaosFaultTimeout)
+	defer cancel()
+	targets, err := faultTargets(ctx, req.Fault.Service, rule.Match.Clusters)
+	if err == nil && len(targets) == 0 {
+		err = fmt.Errorf("no running pods of %s", req.Fault.Service)
+	}
+	if err != nil {
+		e.audit("chaos-fault", req.Fault.Service, err)
+		return nil, err
+	}
+
+	ruleID := e.snapshot().ID
+	undo := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), chaosFaultTimeout)
+		defer cancel()
+		return forEachFaultTarget(targets, func(target string) error {
+			_, err := callFaultAPI(ctx, http.MethodDelete, target+"/admin/faults/"+ruleID, nil)
+			if errors.Is(err, errFaultNotFound) {
+				return nil
+			}
+			return err
+		})
+	}
+	err = forEachFaultTarget(targets, func(target string) error {
+		_, err := callFaultAPI(ctx, http.MethodPut, target+"/admin/faults/"+ruleID, req.Fault.Rule)
+		return err
+	})
+	e.audit("chaos-fault", fmt.Sprintf("%s (%d pods)", req.Fault.Service, len(targets)), err)
+	if err != nil {
+		// 일부 파드에만 주입된 규칙 제거
+		if undoErr := undo(); undoErr != nil {
+			err = errors.Join(err, undoErr)
+		}
+		return nil, err
+	}
+	return undo, nil
+}
+
+// faultTargets returns the base URL of every running pod of the service in the clusters selected
+// by the rule (all member clusters if empty); without Kubernetes it is the route upstream itself
+func faultTargets(ctx context.Context, service string, clusterFilter []string) ([]string, error) {
+	route, ok := gatewayConfig.route(service)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownService, service)
+	}
+	if kubernetesClient == nil {
+		// docker-compose: 서비스마다 인스턴스 하나
+		return []string{strings.TrimSuffix(route.Upstream, "/")}, nil
+	}
+	upstream, err := url.Parse(route.Upstream)
+	if err != nil {
+		return nil, err
+	}
+	port := upstream.Port()
+	if port == "" {
+		port = "80"
+	}
+
+	members, err := clusters.members(ctx)
+	if members == nil {
+		return nil, err
+	}
+	var targets []string
+	for _, member := range members {
+		// 서비스의 FaultMatch.Clusters 와 같이 대소문자 무시
+		if len(clusterFilter) > 0 && !slices.ContainsFunc(clusterFilter, func(name string) bool { return strings.EqualFold(name, member.Name) }) {
+			continue
+		}
+		// 원격 클러스터 파드는 파드 IP 로 직접 연결할 수 있어야 함 (multi-network 이면 rule.match.clusters 로 로컬 클러스터 지정)
+		pods, err := member.Client.CoreV1().Pods(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=" + service})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s pods in %s: %w", service, member.Name, err)
+		}
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" {
+				continue
+			}
+			targets = append(targets, upstream.Scheme+"://"+net.JoinHostPort(pod.Status.PodIP, port))
+		}
+	}
+	return targets, nil
+}
+
+// forEachFaultTarget calls the fault API of every pod concurrently and joins the errors
+func forEachFaultTarget(targets []string, call func(target string) error) error {
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			errs[i] = call(target)
+		}(i, target)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// rollback runs the declared rollback scenario, or undoes the injected fault; an in-service
+// rule is always removed since a scenario does not touch the services' fault rules
+func (e *experiment) rollback(undo func() error) error {
+	req := e.snapshot().Request
+	var err error
+	target := req.Fault.Scenario + req.Fault.Service
+	if req.Rollback.Scenario == "" || req.Fault.Service != "" {
+		err = undo()
+	}
+	if req.Rollback.Scenario != "" {
+		target = req.Rollback.Scenario
+		ctx, cancel := context.WithTimeout(context.Background(), chaosFaultTimeout)
+		defer cancel()
+		_, scenarioErr := scenarios.apply(ctx, req.Rollback.Scenario)
+		err = errors.Join(err, scenarioErr)
+	}
+	e.audit("chaos-rollback", target, err)
+	return err
+}
+
+func (e *experiment) audit(action, target string, err error) {
+	record := AuditRecord{
+		Actor:  "chaos-runner:" + e.snapshot().ID,
+		Action: action,
+		Target: target,
+		Result: "success",
+	}
+	if err != nil {
+		record.Result = "failed"
+		record.ErrorMsg = err.Error()
+	}
+	audit.add(record)
+}
+
+func (e *experiment) finish(status, message string) {
+	slog.Info("Chaos experiment finished", "experiment", e.snapshot().ID, "status", status, "message", message)
+	e.update(func(s *ChaosExperiment) {
+		s.Status = status
+		s.Message = message
+		s.FinishedAt = time.Now().Format(time.RFC3339)
+	})
+}
+
+// callFaultAPI sends a request to a service's in-service fault API
+func callFaultAPI(ctx context.Context, method, target string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("ADMIN_TOKEN"))
+
+	resp, err := tracedHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s %s: %w", method, target, errFaultNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: %d %s", method, target, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// evaluateHypotheses checks every hypothesis against the gateway records of the experiment
+func evaluateHypotheses(hypotheses []ChaosHypothesis, records []TrafficRecord) ([]HypothesisResult, bool) {
+	results := make([]HypothesisResult, 0, len(hypotheses))
+	allPassed := true
+
+	for _, hypothesis := range hypotheses {
+		latencies := []float64{}
+		errors := 0
+		for _, record := range records {
+			if hypothesis.Service != "" && record.Service != hypothesis.Service {
+				continue
+			}
+			latencies = append(latencies, record.LatencyMs)
+			if record.IsError() {
+				errors++
+			}
+		}
+		sort.Float64s(latencies)
+
+		result := HypothesisResult{ChaosHypothesis: hypothesis, Samples: len(latencies)}
+		if len(latencies) > 0 {
+			switch hypothesis.Metric {
+			case MetricErrorRate:
+				result.Observed = float64(errors) / float64(len(latencies))
+			case MetricP95Ms:
+				result.Observed = loadgen.Percentile(latencies, 95)
+			}
+		}
+		// 표본이 없으면 가설을 검증할 수 없으므로 실패로 간주
+		result.Passed = result.Samples > 0 && result.Observed < hypothesis.Max
+		allPassed = allPassed && result.Passed
+		results = append(results, result)
+	}
+	return results, allPassed
+}
+
+// chaosHandler serves /admin/chaos, /admin/chaos/{id} and /admin/chaos/{id}/abort
+func chaosHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/chaos"), "/")
+
+	if path == "" {
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(chaos.list())
+		case http.MethodPost:
+			createChaosHandler(w, r)
+		default:
+			platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	e, ok := chaos.get(parts[0])
+	if !ok {
+		platform.Error(w, r, http.StatusNotFound, platform.CodeNotFound, "Experiment not found")
+		return
+	}
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
+			return
+		}
+		json.NewEncoder(w).Encode(e.snapshot())
+		return
+	}
+
+	if r.Method != http.MethodPost || parts[1] != "abort" {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid experiment operation")
+		return
+	}
+	state := e.snapshot()
+	if state.Status != ChaosScheduled && state.Status != ChaosRunning {
+		platform.Error(w, r, http.StatusConflict, codeConflict, fmt.Sprintf("experiment %s is %s", state.ID, state.Status))
+		return
+	}
+	e.cancel()
+	audit.add(AuditRecord{
+		Actor:  adminActor(r),
+		Remote: r.RemoteAddr,
+		Action: "chaos-abort",
+		Target: state.ID,
+		Result: "accepted",
+	})
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(e.snapshot())
+}
+
+func createChaosHandler(w http.ResponseWriter, r *http.Request) {
+	var req ChaosExperimentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if err := validateChaosRequest(&req); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	e, err := chaos.start(req, adminActor(r))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	state := e.snapshot()
+	audit.add(AuditRecord{
+		Actor:  adminActor(r),
+		Remote: r.RemoteAddr,
+		Action: "chaos-create",
+		Target: req.Name,
+		After:  req,
+		Result: "scheduled " + state.ID,
+	})
+
+	w.Header().Set("Location", "/admin/chaos/"+state.ID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(state)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateChaosRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ChaosExperimentRequest
+		wantErr error
+	}{
+		{name: "scenario", req: ChaosExperimentRequest{Fault: ChaosFault{Scenario: "delay"}}},
+		{name: "in-service rule", req: ChaosExperimentRequest{Fault: ChaosFault{Service: "movie", Rule: []byte(`{"abort":{"percentage":10,"httpStatus":503}}`)}}},
+		{name: "scenario and service", req: ChaosExperimentRequest{Fault: ChaosFault{Scenario: "delay", Service: "movie-service"}}, wantErr: weightValidationError{}},
+		{name: "no fault", req: ChaosExperimentRequest{}, wantErr: weightValidationError{}},
+		{name: "unknown service", req: ChaosExperimentRequest{Fault: ChaosFault{Service: "ticket", Rule: []byte(`{}`)}}, wantErr: errUnknownService},
+		{name: "missing rule", req: ChaosExperimentRequest{Fault: ChaosFault{Service: "movie-service"}}, wantErr: weightValidationError{}},
+		{name: "rule is not an object", req: ChaosExperimentRequest{Fault: ChaosFault{Service: "movie-service", Rule: []byte(`[1]`)}}, wantErr: weightValidationError{}},
+		{name: "rule sets id", req: ChaosExperimentRequest{Fault: ChaosFault{Service: "movie-service", Rule: []byte(`{"id":"x"}`)}}, wantErr: weightValidationError{}},
+		{name: "bad startAt", req: ChaosExperimentRequest{Fault: ChaosFault{Scenario: "delay"}, StartAt: "tomorrow"}, wantErr: weightValidationError{}},
+		{name: "rate above 200", req: ChaosExperimentRequest{Fault: ChaosFault{Scenario: "delay"}, Load: ChaosLoad{RatePerSecond: 500}}, wantErr: weightValidationError{}},
+		{name: "unknown metric", req: ChaosExperimentRequest{Fault: ChaosFault{Scenario: "delay"}, Hypotheses: []ChaosHypothesis{{Metric: "p50Ms", Max: 1}}}, wantErr: weightValidationError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeIstio(t)
+			err := validateChaosRequest(&tt.req)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("validateChaosRequest() error = %v", err)
+				}
+				// 기본값: 60초, 초당 10 요청, 모든 라우트, 오류율/p95 가설
+				if tt.req.DurationSeconds != 60 || tt.req.Load.RatePerSecond != 10 || len(tt.req.Load.Paths) != 3 || len(tt.req.Hypotheses) != 2 || tt.req.Name == "" {
+					t.Errorf("defaults = %+v", tt.req)
+				}
+				return
+			}
+			var validation weightValidationError
+			if _, ok := tt.wantErr.(weightValidationError); ok && !errors.As(err, &validation) {
+				t.Errorf("error = %v, want a weightValidationError", err)
+			} else if !ok && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateHypotheses(t *testing.T) {
+	records := []TrafficRecord{
+		{Service: "movie-service", StatusCode: 200, LatencyMs: 10},
+		{Service: "movie-service", StatusCode: 200, LatencyMs: 20},
+		{Service: "movie-service", StatusCode: 503, LatencyMs: 900},
+		{Service: "user-service", StatusCode: 200, LatencyMs: 5},
+	}
+	tests := []struct {
+		name         string
+		hypothesis   ChaosHypothesis
+		wantObserved float64
+		wantSamples  int
+		wantPassed   bool
+	}{
+		{name: "gateway error rate", hypothesis: ChaosHypothesis{Metric: MetricErrorRate, Max: 0.5}, wantObserved: 0.25, wantSamples: 4, wantPassed: true},
+		{name: "service error rate", hypothesis: ChaosHypothesis{Service: "movie-service", Metric: MetricErrorRate, Max: 0.1}, wantObserved: 1.0 / 3, wantSamples: 3},
+		{name: "service p95", hypothesis: ChaosHypothesis{Service: "movie-service", Metric: MetricP95Ms, Max: 500}, wantObserved: 900, wantSamples: 3},
+		{name: "p95 within limit", hypothesis: ChaosHypothesis{Service: "user-service", Metric: MetricP95Ms, Max: 500}, wantObserved: 5, wantSamples: 1, wantPassed: true},
+		{name: "no samples fail", hypothesis: ChaosHypothesis{Service: "booking-service", Metric: MetricErrorRate, Max: 0.01}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, passed := evaluateHypotheses([]ChaosHypothesis{tt.hypothesis}, records)
+			got := results[0]
+			if got.Observed != tt.wantObserved || got.Samples != tt.wantSamples || got.Passed != tt.wantPassed || passed != tt.wantPassed {
+				t.Errorf("result = %+v (all passed %v), want observed %v, samples %d, passed %v", got, passed, tt.wantObserved, tt.wantSamples, tt.wantPassed)
+			}
+		})
+	}
+}
+
+// waitForChaosStatus polls until the experiment reaches status
+func waitForChaosStatus(t *testing.T, e *experiment, status string) ChaosExperiment {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if state := e.snapshot(); state.Status == status {
+			return state
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	state := e.snapshot()
+	t.Fatalf("experiment status = %s (%s), want %s", state.Status, state.Message, status)
+	return state
+}
+
+func TestChaosConflicts(t *testing.T) {
+	useFakeIstio(t)
+	useTestControllers(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	later := time.Now().Add(time.Hour).Format(time.RFC3339)
+	start := func(fault ChaosFault) (*experiment, error) {
+		req := ChaosExperimentRequest{Fault: fault, StartAt: later}
+		if err := validateChaosRequest(&req); err != nil {
+			t.Fatal(err)
+		}
+		return chaos.start(req, "test")
+	}
+	movieRule := []byte(`{"abort":{"percentage":10,"httpStatus":503}}`)
+
+	movie, err := start(ChaosFault{Service: "movie-service", Rule: movieRule})
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	if _, err := start(ChaosFault{Service: "movie-service", Rule: movieRule}); !errors.Is(err, errChaosConflict) {
+		t.Errorf("second movie-service experiment error = %v, want %v", err, errChaosConflict)
+	}
+	if _, err := start(ChaosFault{Scenario: "delay"}); !errors.Is(err, errChaosConflict) {
+		t.Errorf("scenario experiment error = %v, want %v", err, errChaosConflict)
+	}
+	if _, err := start(ChaosFault{Service: "user-service", Rule: movieRule}); err != nil {
+		t.Errorf("user-service experiment error = %v, want nil", err)
+	}
+	if _, ok := chaos.activeFor("movie-service"); !ok {
+		t.Error("activeFor(movie-service) = false, want the scheduled experiment")
+	}
+
+	// 중단 요청은 202, 끝난 실험의 중단은 409
+	abort := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/admin/chaos/"+movie.snapshot().ID+"/abort", nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		adminHandler(w, r)
+		return w
+	}
+	if w := abort(); w.Code != http.StatusAccepted {
+		t.Fatalf("abort status = %d (%s), want 202", w.Code, w.Body.String())
+	}
+	waitForChaosStatus(t, movie, ChaosAborted)
+	if w := abort(); w.Code != http.StatusConflict {
+		t.Errorf("second abort status = %d, want 409", w.Code)
+	}
+	if _, ok := chaos.activeFor("movie-service"); ok {
+		t.Error("activeFor(movie-service) = true after abort, want false")
+	}
+}
+
+func TestChaosInServiceFault(t *testing.T) {
+	useFakeIstio(t)
+	useTestControllers(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+	previousTraffic, previousKubernetes := traffic, kubernetesClient
+	traffic, kubernetesClient = newTrafficStore(1000), nil
+	t.Cleanup(func() { traffic, kubernetesClient = previousTraffic, previousKubernetes })
+
+	// 서비스의 장애 주입 API
+	var mu sync.Mutex
+	var calls []string
+	faultAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization"))
+		mu.Unlock()
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer faultAPI.Close()
+	gatewayConfig.Routes[1].Upstream = faultAPI.URL
+
+	// 부하를 받는 게이트웨이 대신 요청마다 통계를 기록
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traffic.add(TrafficRecord{Service: "movie-service", Cluster: "ctx1", StatusCode: http.StatusOK, LatencyMs: 5})
+	}))
+	defer gateway.Close()
+	t.Setenv("CHAOS_LOAD_TARGET", gateway.URL)
+
+	req := ChaosExperimentRequest{
+		Fault:           ChaosFault{Service: "movie-service", Rule: []byte(`{"abort":{"percentage":10,"httpStatus":503}}`)},
+		DurationSeconds: 1,
+		Load:            ChaosLoad{RatePerSecond: 20, Paths: []string{"/movies/"}},
+		Hypotheses:      []ChaosHypothesis{{Service: "movie-service", Metric: MetricErrorRate, Max: 0.01}},
+	}
+	if err := validateChaosRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	e, err := chaos.start(req, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := waitForChaosStatus(t, e, ChaosPassed)
+
+	if state.Load.Sent == 0 || len(state.Hypotheses) != 1 || state.Hypotheses[0].Samples == 0 {
+		t.Errorf("load = %+v, hypotheses = %+v; want samples from the load", state.Load, state.Hypotheses)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{
+		"PUT /admin/faults/" + state.ID + " Bearer secret",
+		"DELETE /admin/faults/" + state.ID + " Bearer secret",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("fault API calls = %q, want %q", calls, want)
+	}
+
+	records := audit.list()
+	var actions []string
+	for _, record := range records[len(records)-2:] {
+		actions = append(actions, record.Action+" "+record.Result)
+	}
+	if got := strings.Join(actions, ", "); got != "chaos-fault success, chaos-rollback success" {
+		t.Errorf("audit = %s, want the fault and its rollback", got)
+	}
+}
+
+func TestChaosFaultInjectionFailure(t *testing.T) {
+	useFakeIstio(t)
+	useTestControllers(t)
+	previous := kubernetesClient
+	kubernetesClient = nil
+	t.Cleanup(func() { kubernetesClient = previous })
+
+	faultAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"code": "UNAUTHORIZED"})
+	}))
+	defer faultAPI.Close()
+	gatewayConfig.Routes[1].Upstream = faultAPI.URL
+
+	req := ChaosExperimentRequest{Fault: ChaosFault{Service: "movie-service", Rule: []byte(`{"abort":{"percentage":10,"httpStatus":503}}`)}, DurationSeconds: 1}
+	if err := validateChaosRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	e, err := chaos.start(req, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := waitForChaosStatus(t, e, ChaosError)
+	if !strings.Contains(state.Message, "failed to inject fault") || !strings.Contains(state.Message, "401") {
+		t.Errorf("message = %q, want the rejected fault injection", state.Message)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// defaultRemoteSecret holds the kubeconfigs of the other clusters in the gateway namespace; the
+// gateway reads only this secret, never the Istio remote secrets next to the mesh CA in istio-system
+const defaultRemoteSecret = "theater-msa-remote-clusters"
+
+// memberCluster is the Kubernetes client of one cluster of the mesh
+type memberCluster struct {
+	Name   string
+	Local  bool
+	Client kubernetes.Interface
+}
+
+// ClusterDeploymentStatus is the deployment status of one cluster
+type ClusterDeploymentStatus struct {
+	Cluster     string           `json:"cluster"`
+	Local       bool             `json:"local"`
+	Reachable   bool             `json:"reachable"`
+	Error       string           `json:"error,omitempty"`
+	Deployments []DeploymentInfo `json:"deployments"`
+}
+
+// clusterRegistry caches the clients built from remote secrets and kubeconfig contexts
+type clusterRegistry struct {
+	mu          sync.Mutex
+	static      map[string]memberCluster // --context 로 지정한 원격 클러스터
+	remotes     map[string]memberCluster
+	kubeconfigs map[string][]byte // 클러스터명 -> 클라이언트를 만든 kubeconfig, 내용이 바뀐 키만 다시 만듦
+	loadErr     error
+	loadedAt    time.Time
+	refreshing  bool // 한 호출만 시크릿을 조회하고 나머지는 이전 목록을 사용
+}
+
+var clusters = &clusterRegistry{
+	static:      map[string]memberCluster{},
+	remotes:     map[string]memberCluster{},
+	kubeconfigs: map[string][]byte{},
+}
+
+// defaultLocalCluster is the local cluster name when CLUSTER_NAME is unset; the first --context overrides it
+var defaultLocalCluster = "ctx1"
+
+// localClusterName is the name of the cluster the gateway runs in
+func localClusterName() string {
+	return getEnvString("CLUSTER_NAME", defaultLocalCluster)
+}
+
+// addStatic registers a remote cluster configured on the command line
+func (c *clusterRegistry) addStatic(member memberCluster) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.static[member.Name] = member
+}
+
+// members returns the local cluster followed by the remote clusters, refreshing the secrets periodically
+func (c *clusterRegistry) members(ctx context.Context) ([]memberCluster, error) {
+	if kubernetesClient == nil {
+		return nil, fmt.Errorf("kubernetes client not available")
+	}
+
+	refresh := time.Duration(getEnvInt("CLUSTER_REFRESH_SECONDS", 60)) * time.Second
+	c.mu.Lock()
+	due := !c.refreshing && time.Since(c.loadedAt) > refresh
+	if due {
+		c.refreshing = true
+	}
+	c.mu.Unlock()
+
+	if due {
+		// 시크릿 조회는 잠금 밖에서: API 서버가 느려도 다른 요청의 members 호출을 막지 않음
+		secret, source, err := fetchRemoteSecret(ctx)
+		c.mu.Lock()
+		if err == nil {
+			err = c.applyRemotesLocked(secret, source)
+		}
+		c.loadErr = err
+		c.loadedAt = time.Now()
+		c.refreshing = false
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 명령행 컨텍스트가 같은 이름의 원격 시크릿보다 우선
+	remotes := map[string]memberCluster{}
+	for name, member := range c.remotes {
+		remotes[name] = member
+	}
+	for name, member := range c.static {
+		remotes[name] = member
+	}
+
+	members := []memberCluster{{Name: localClusterName(), Local: true, Client: kubernetesClient}}
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		members = append(members, remotes[name])
+	}
+	return members, c.loadErr
+}
+
+// fetchRemoteSecret reads the remote clusters secret; a missing secret is an empty one (single cluster)
+func fetchRemoteSecret(ctx context.Context) (*v1.Secret, string, error) {
+	namespace := getEnvString("REMOTE_SECRET_NAMESPACE", gatewayConfig.Namespace)
+	secretName := getEnvString("REMOTE_SECRET_NAME", defaultRemoteSecret)
+	source := namespace + "/" + secretName
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	secret, err := kubernetesClient.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// 단일 클러스터 구성
+		return &v1.Secret{}, source, nil
+	}
+	if err != nil {
+		return nil, source, fmt.Errorf("failed to get remote secret %s: %w", source, err)
+	}
+	return secret, source, nil
+}
+
+// applyRemotesLocked builds clients from the remote clusters secret; every data key is a cluster name with its kubeconfig.
+// A client is rebuilt only when its own kubeconfig changed, so editing one key keeps the other clusters' clients.
+func (c *clusterRegistry) applyRemotesLocked(secret *v1.Secret, source string) error {
+	seen := map[string]bool{}
+	var errs []string
+	for name, kubeconfig := range secret.Data {
+		if name == localClusterName() {
+			continue
+		}
+		seen[name] = true
+		if _, ok := c.remotes[name]; ok && bytes.Equal(c.kubeconfigs[name], kubeconfig) {
+			continue
+		}
+
+		config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid kubeconfig in secret %s: %v", name, source, err))
+			continue
+		}
+		config.Timeout = 5 * time.Second
+		client, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		c.remotes[name] = memberCluster{Name: name, Client: client}
+		c.kubeconfigs[name] = kubeconfig
+		slog.Info("Registered remote cluster from secret", "remote_cluster", name, "secret", source)
+	}
+
+	for name := range c.remotes {
+		if !seen[name] {
+			delete(c.remotes, name)
+			delete(c.kubeconfigs, name)
+			slog.Info("Removed remote cluster: secret no longer present", "remote_cluster", name)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("remote secrets: %v", errs)
+	}
+	return nil
+}
+
+// collectDeploymentStatus lists the sample namespace pods of every cluster concurrently
+func collectDeploymentStatus(ctx context.Context) ([]ClusterDeploymentStatus, error) {
+	members, discoveryErr := clusters.members(ctx)
+	if members == nil {
+		return nil, discoveryErr
+	}
+	if discoveryErr != nil {
+		slog.WarnContext(ctx, "Cluster discovery incomplete", "error", discoveryErr)
+	}
+
+	statuses := make([]ClusterDeploymentStatus, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, member memberCluster) {
+			defer wg.Done()
+			statuses[i] = clusterDeploymentStatus(ctx, member)
+		}(i, member)
+	}
+	wg.Wait()
+	return statuses, discoveryErr
+}
+
+func clusterDeploymentStatus(ctx context.Context, member memberCluster) ClusterDeploymentStatus {
+	status := ClusterDeploymentStatus{Cluster: member.Name, Local: member.Local, Deployments: []DeploymentInfo{}}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	pods, err := member.Client.CoreV1().Pods(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		status.Error = err.Error()
+		slog.WarnContext(ctx, "Failed to list pods", "remote_cluster", member.Name, "error", err)
+		return status
+	}
+	status.Reachable = true
+
+	for i := range pods.Items {
+		deployment := createDeploymentInfo(&pods.Items[i], member.Name)
+		if deployment.Service != "unknown" {
+			status.Deployments = append(status.Deployments, deployment)
+		}
+	}
+	sort.Slice(status.Deployments, func(i, j int) bool {
+		if status.Deployments[i].Service != status.Deployments[j].Service {
+			return status.Deployments[i].Service < status.Deployments[j].Service
+		}
+		return status.Deployments[i].PodName < status.Deployments[j].PodName
+	})
+	return status
+}
+
+// podStatus reports the pod status the way kubectl does: waiting reasons and termination take precedence
+func podStatus(pod *v1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if waiting := containerStatus.State.Waiting; waiting != nil && waiting.Reason != "" && waiting.Reason != "ContainerCreating" {
+			return waiting.Reason
+		}
+	}
+	if pod.Status.Phase == v1.PodRunning {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if !containerStatus.Ready {
+				return "Not Ready"
+			}
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
+// formatAge renders a duration like kubectl's AGE column
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// testKubeconfig is a minimal kubeconfig pointing at server
+func testKubeconfig(server string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: %s
+users:
+- name: remote
+  user:
+    token: test
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+`, server))
+}
+
+// useTestClusters replaces the local client and the cluster registry; every members call refreshes the secret
+func useTestClusters(t *testing.T) *k8sfake.Clientset {
+	t.Helper()
+	t.Setenv("CLUSTER_NAME", "ctx1")
+	t.Setenv("CLUSTER_REFRESH_SECONDS", "0")
+	previousClient, previousClusters, previousConfig := kubernetesClient, clusters, gatewayConfig
+	client := k8sfake.NewSimpleClientset()
+	kubernetesClient, gatewayConfig = client, defaultGatewayConfig()
+	clusters = &clusterRegistry{static: map[string]memberCluster{}, remotes: map[string]memberCluster{}, kubeconfigs: map[string][]byte{}}
+	t.Cleanup(func() {
+		kubernetesClient, clusters, gatewayConfig = previousClient, previousClusters, previousConfig
+	})
+	return client
+}
+
+func setRemoteSecret(t *testing.T, client *k8sfake.Clientset, data map[string][]byte) {
+	t.Helper()
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: defaultRemoteSecret, Namespace: "theater-msa"}, Data: data}
+	secrets := client.CoreV1().Secrets("theater-msa")
+	if _, err := secrets.Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
+		if _, err := secrets.Create(context.Background(), secret, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// memberClients returns the clients of members by cluster name
+func memberClients(t *testing.T, wantErr bool) map[string]kubernetes.Interface {
+	t.Helper()
+	members, err := clusters.members(context.Background())
+	if (err != nil) != wantErr {
+		t.Fatalf("members() error = %v, wantErr %v", err, wantErr)
+	}
+	clients := map[string]kubernetes.Interface{}
+	for i, member := range members {
+		if member.Local != (i == 0) {
+			t.Errorf("member %d %s: local = %v", i, member.Name, member.Local)
+		}
+		clients[member.Name] = member.Client
+	}
+	return clients
+}
+
+func TestClusterMembersFromSecret(t *testing.T) {
+	client := useTestClusters(t)
+
+	// 시크릿이 없으면 단일 클러스터
+	if got := memberClients(t, false); len(got) != 1 || got["ctx1"] != kubernetesClient {
+		t.Fatalf("members without secret = %v, want only the local cluster", got)
+	}
+
+	setRemoteSecret(t, client, map[string][]byte{
+		"ctx1": testKubeconfig("https://ctx1.example:6443"), // 로컬 클러스터 키는 무시
+		"ctx2": testKubeconfig("https://ctx2.example:6443"),
+		"ctx3": testKubeconfig("https://ctx3.example:6443"),
+	})
+	first := memberClients(t, false)
+	if len(first) != 3 || first["ctx1"] != kubernetesClient || first["ctx2"] == nil || first["ctx3"] == nil {
+		t.Fatalf("members = %v, want ctx1 (local), ctx2, ctx3", first)
+	}
+
+	// ctx3 만 바뀌면 ctx2 클라이언트는 그대로
+	setRemoteSecret(t, client, map[string][]byte{
+		"ctx2": testKubeconfig("https://ctx2.example:6443"),
+		"ctx3": testKubeconfig("https://ctx3-new.example:6443"),
+	})
+	second := memberClients(t, false)
+	if second["ctx2"] != first["ctx2"] {
+		t.Error("ctx2 client was rebuilt although its kubeconfig did not change")
+	}
+	if second["ctx3"] == first["ctx3"] {
+		t.Error("ctx3 client was not rebuilt after its kubeconfig changed")
+	}
+
+	// 잘못된 kubeconfig 는 오류로 보고하고 나머지 클러스터는 유지, 빠진 키는 제거
+	setRemoteSecret(t, client, map[string][]byte{
+		"ctx2": testKubeconfig("https://ctx2.example:6443"),
+		"ctx4": []byte("not a kubeconfig"),
+	})
+	third := memberClients(t, true)
+	if len(third) != 2 || third["ctx2"] != first["ctx2"] {
+		t.Errorf("members = %v, want ctx1 and the unchanged ctx2", third)
+	}
+}
+
+func TestClusterMembersConcurrent(t *testing.T) {
+	client := useTestClusters(t)
+	setRemoteSecret(t, client, map[string][]byte{"ctx2": testKubeconfig("https://ctx2.example:6443")})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := clusters.members(context.Background()); err != nil {
+				t.Errorf("members() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := memberClients(t, false); len(got) != 2 {
+		t.Errorf("members = %v, want ctx1 and ctx2", got)
+	}
+}
@@ -0,0 +1,99 @@
+// Command loadgen sends a reproducible request mix through the API gateway and
+// reports the per-service cluster distribution, status codes and latencies.
+//
+//	go run ./cmd/loadgen -url https://theater.$DOMAIN -rate 20 -duration 60s -mix users=2,movies=1,bookings=1
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"msa-sample-01/api-gateway/loadgen"
+)
+
+// headerFlags collects repeated -H "Name: value" flags
+type headerFlags http.Header
+
+func (h headerFlags) String() string { return fmt.Sprint(http.Header(h)) }
+
+func (h headerFlags) Set(value string) error {
+	name, val, found := strings.Cut(value, ":")
+	if !found {
+		return fmt.Errorf("header must be \"Name: value\", got %q", value)
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(val))
+	return nil
+}
+
+func main() {
+	headers := headerFlags{}
+	baseURL := flag.String("url", "http://localhost:8080", "gateway base URL")
+	rate := flag.Float64("rate", 10, "requests per second (0 = as fast as concurrency allows)")
+	concurrency := flag.Int("concurrency", 10, "maximum in-flight requests")
+	duration := flag.Duration("duration", 30*time.Second, "how long to send requests")
+	requests := flag.Int("requests", 0, "stop after this many requests (0 = until duration)")
+	mix := flag.String("mix", "users=1,movies=1,bookings=1", "weighted request mix")
+	timeout := flag.Duration("timeout", 10*time.Second, "per-request timeout")
+	format := flag.String("format", "text", "output format: text, json or both")
+	output := flag.String("o", "", "write the JSON report to this file")
+	flag.Var(headers, "H", "extra request header, e.g. -H \"x-canary: true\" (repeatable)")
+	flag.Parse()
+
+	targets, err := loadgen.ParseMix(*mix)
+	if err != nil {
+		log.Fatalf("Invalid -mix: %v", err)
+	}
+	if *requests > 0 && !isFlagSet("duration") {
+		*duration = 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := loadgen.Run(ctx, loadgen.Config{
+		BaseURL:     *baseURL,
+		Targets:     targets,
+		Rate:        *rate,
+		Concurrency: *concurrency,
+		Duration:    *duration,
+		Requests:    *requests,
+		Timeout:     *timeout,
+		Headers:     http.Header(headers),
+	})
+	if err != nil {
+		log.Fatalf("Load run failed: %v", err)
+	}
+
+	if *format == "text" || *format == "both" {
+		report.WriteText(os.Stdout)
+	}
+	if *format == "json" || *format == "both" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+	if *output != "" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		if err := os.WriteFile(*output, data, 0o644); err != nil {
+			log.Fatalf("Could not write report: %v", err)
+		}
+	}
+}
+
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // 이미지에 tzdata 가 없어도 timezone 검증이 동작하도록 내장
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// RouteConfig maps a path prefix to a backend service and the VirtualService that routes it
+type RouteConfig struct {
+	Service        string `json:"service"` // Kubernetes 서비스명 = 파드 app 레이블
+	PathPrefix     string `json:"pathPrefix"`
+	Upstream       string `json:"upstream"`
+	GRPCUpstream   string `json:"grpcUpstream,omitempty"`   // host:port, grpcTranscoding 이 켜져 있을 때 사용
+	VirtualService string `json:"virtualService,omitempty"` // 비어 있으면 가중치 관리 대상에서 제외
+	// Weights are the subset weights shown and compared while the VirtualService cannot be read (<SERVICE>_<SUBSET>_WEIGHT)
+	Weights     map[string]int `json:"weights,omitempty"`
+	DisplayName string         `json:"displayName"`
+	Icon        string         `json:"icon"`
+}
+
+// WorkloadConfig describes a workload shown in the UI that is not routed by the gateway
+type WorkloadConfig struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Icon        string `json:"icon"`
+	Port        string `json:"port"`
+}
+
+// ServerConfig configures the HTTP server and graceful shutdown; durations use Go syntax ("30s")
+type ServerConfig struct {
+	ReadTimeout       metav1.Duration `json:"readTimeout"`
+	ReadHeaderTimeout metav1.Duration `json:"readHeaderTimeout"`
+	WriteTimeout      metav1.Duration `json:"writeTimeout"` // /events 스트림에는 적용하지 않음
+	IdleTimeout       metav1.Duration `json:"idleTimeout"`
+	MaxHeaderBytes    int             `json:"maxHeaderBytes"`
+	DrainDelay        metav1.Duration `json:"drainDelay"`      // SIGTERM 후 readiness 실패 상태로 요청을 계속 받는 시간
+	ShutdownTimeout   metav1.Duration `json:"shutdownTimeout"` // 진행 중 요청 완료를 기다리는 최대 시간
+}
+
+// serveConfig converts the YAML settings for platform.Serve
+func (c ServerConfig) serveConfig(port int) platform.ServerConfig {
+	return platform.ServerConfig{
+		Port:              port,
+		ReadTimeout:       c.ReadTimeout.Duration,
+		ReadHeaderTimeout: c.ReadHeaderTimeout.Duration,
+		WriteTimeout:      c.WriteTimeout.Duration,
+		IdleTimeout:       c.IdleTimeout.Duration,
+		MaxHeaderBytes:    c.MaxHeaderBytes,
+		DrainDelay:        c.DrainDelay.Duration,
+		ShutdownTimeout:   c.ShutdownTimeout.Duration,
+	}
+}
+
+// GatewayConfig is the deployment-specific configuration of the gateway
+type GatewayConfig struct {
+	Namespace string           `json:"namespace"`
+	Timezone  string           `json:"timezone"`
+	Port      int              `json:"port"`
+	Server    ServerConfig     `json:"server"`
+	Routes    []RouteConfig    `json:"routes"`
+	Workloads []WorkloadConfig `json:"workloads"`
+	// OpenAPIValidation checks /admin/ calls against openapi.yaml: off, request or full (responses too)
+	OpenAPIValidation string `json:"openapiValidation"`
+	// GRPCTranscoding serves the known REST operations of the routes by calling their gRPC API
+	GRPCTranscoding bool `json:"grpcTranscoding"`
+
+	location *time.Location
+}
+
+// reservedPrefixes are served by the gateway itself and cannot be used as route prefixes
+var reservedPrefixes = []string{"/admin/", "/healthz", "/readyz", "/startupz", "/deployment-status", "/traffic-", "/events", "/topology", "/metrics", "/openapi.json", "/docs", "/graphql"}
+
+func defaultGatewayConfig() *GatewayConfig {
+	return &GatewayConfig{
+		Namespace:         "theater-msa",
+		Timezone:          "Asia/Seoul",
+		Port:              8080,
+		OpenAPIValidation: validationOff,
+		Server: ServerConfig{
+			ReadTimeout:       metav1.Duration{Duration: 30 * time.Second},
+			ReadHeaderTimeout: metav1.Duration{Duration: 10 * time.Second},
+			WriteTimeout:      metav1.Duration{Duration: 60 * time.Second},
+			IdleTimeout:       metav1.Duration{Duration: 120 * time.Second},
+			MaxHeaderBytes:    1 << 20,
+			DrainDelay:        metav1.Duration{Duration: 5 * time.Second},
+			ShutdownTimeout:   metav1.Duration{Duration: 20 * time.Second},
+		},
+		Routes: []RouteConfig{
+			{Service: "user-service", PathPrefix: "/users/", Upstream: "http://user-service:8081", GRPCUpstream: "user-service:9081", VirtualService: "user-service-vs", Weights: map[string]int{"ctx1": 70, "ctx2": 30}, DisplayName: "User Service", Icon: "👤"},
+			{Service: "movie-service", PathPrefix: "/movies/", Upstream: "http://movie-service:8082", GRPCUpstream: "movie-service:9082", VirtualService: "movie-service-vs", Weights: map[string]int{"ctx1": 30, "ctx2": 70}, DisplayName: "Movie Service", Icon: "🎬"},
+			{Service: "booking-service", PathPrefix: "/bookings/", Upstream: "http://booking-service:8083", GRPCUpstream: "booking-service:9083", VirtualService: "booking-service-vs", Weights: map[string]int{"ctx1": 50, "ctx2": 50}, DisplayName: "Booking Service", Icon: "🎟️"},
+		},
+		Workloads: []WorkloadConfig{
+			{Name: "api-gateway", DisplayName: "API Gateway", Icon: "🌐", Port: "8080"},
+			{Name: "redis", DisplayName: "Redis", Icon: "💾", Port: "6379"},
+			{Name: "eastwest-gateway", DisplayName: "East-West Gateway", Icon: "🌉", Port: "15443"},
+		},
+	}
+}
+
+// gatewayConfig holds the defaults until loadGatewayConfig replaces it at startup
+var gatewayConfig = defaultGatewayConfig()
+
+// loadGatewayConfig reads the YAML file (if any) over the defaults, applies env overrides and validates the result
+func loadGatewayConfig(path string) (*GatewayConfig, error) {
+	cfg := defaultGatewayConfig()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+		// 기본값 위에 직접 디코딩하면 슬라이스 원소가 재사용되므로 별도로 읽어 병합
+		var file GatewayConfig
+		if err := yaml.UnmarshalStrict(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+		cfg.merge(file)
+	}
+	cfg.applyEnv()
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid gateway config: %w", err)
+	}
+	return cfg, nil
+}
+
+// merge overrides the defaults with the fields set in the config file; routes and workloads are replaced as a whole
+func (c *GatewayConfig) merge(file GatewayConfig) {
+	if file.Namespace != "" {
+		c.Namespace = file.Namespace
+	}
+	if file.Timezone != "" {
+		c.Timezone = file.Timezone
+	}
+	if file.Port != 0 {
+		c.Port = file.Port
+	}
+	c.Server.merge(file.Server)
+	if file.Routes != nil {
+		c.Routes = file.Routes
+	}
+	if file.Workloads != nil {
+		c.Workloads = file.Workloads
+	}
+	if file.OpenAPIValidation != "" {
+		c.OpenAPIValidation = file.OpenAPIValidation
+	}
+	if file.GRPCTranscoding {
+		c.GRPCTranscoding = true
+	}
+}
+
+// merge overrides the server settings set in the config file
+func (s *ServerConfig) merge(file ServerConfig) {
+	for _, field := range []struct{ value, file *metav1.Duration }{
+		{&s.ReadTimeout, &file.ReadTimeout},
+		{&s.ReadHeaderTimeout, &file.ReadHeaderTimeout},
+		{&s.WriteTimeout, &file.WriteTimeout},
+		{&s.IdleTimeout, &file.IdleTimeout},
+		{&s.DrainDelay, &file.DrainDelay},
+		{&s.ShutdownTimeout, &file.ShutdownTimeout},
+	} {
+		if field.file.Duration != 0 {
+			*field.value = *field.file
+		}
+	}
+	if file.MaxHeaderBytes != 0 {
+		s.MaxHeaderBytes = file.MaxHeaderBytes
+	}
+}
+
+// envDuration overrides a duration from the environment; invalid values become -1 so validate rejects them
+func envDuration(key string, d *metav1.Duration) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		parsed = -1
+	}
+	d.Duration = parsed
+}
+
+// envPrefix turns "user-service" into "USER_SERVICE", matching USER_SERVICE_CTX1_WEIGHT
+func envPrefix(service string) string {
+	return strings.ToUpper(strings.ReplaceAll(service, "-", "_"))
+}
+
+// applyEnv overrides the file with GATEWAY_* and <SERVICE>_* environment variables
+func (c *GatewayConfig) applyEnv() {
+	c.Namespace = getEnvString("GATEWAY_NAMESPACE", c.Namespace)
+	c.Timezone = getEnvString("GATEWAY_TIMEZONE", c.Timezone)
+	c.OpenAPIValidation = getEnvString("OPENAPI_VALIDATION", c.OpenAPIValidation) // 서비스와 같은 환경변수
+	if value := os.Getenv("GRPC_TRANSCODING"); value != "" {
+		c.GRPCTranscoding = value == "true"
+	}
+	if value := os.Getenv("GATEWAY_PORT"); value != "" {
+		// 잘못된 값은 validate 에서 거부되도록 0으로 둔다
+		c.Port, _ = strconv.Atoi(value)
+	}
+	// 서비스와 같은 HTTP_* / SHUTDOWN_* 환경변수
+	envDuration("HTTP_READ_TIMEOUT", &c.Server.ReadTimeout)
+	envDuration("HTTP_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout)
+	envDuration("HTTP_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	envDuration("HTTP_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	envDuration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	if value := os.Getenv("HTTP_MAX_HEADER_BYTES"); value != "" {
+		maxHeaderBytes, err := strconv.Atoi(value)
+		if err != nil {
+			maxHeaderBytes = -1
+		}
+		c.Server.MaxHeaderBytes = maxHeaderBytes
+	}
+	if value := os.Getenv("SHUTDOWN_DRAIN_SECONDS"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			seconds = -1
+		}
+		c.Server.DrainDelay.Duration = time.Duration(seconds) * time.Second
+	}
+	for i := range c.Routes {
+		route := &c.Routes[i]
+		prefix := envPrefix(route.Service)
+		route.PathPrefix = getEnvString(prefix+"_PATH_PREFIX", route.PathPrefix)
+		route.Upstream = getEnvString(prefix+"_UPSTREAM", route.Upstream)
+		route.GRPCUpstream = getEnvString(prefix+"_GRPC_UPSTREAM", route.GRPCUpstream)
+		route.VirtualService = getEnvString(prefix+"_VIRTUAL_SERVICE", route.VirtualService)
+		if len(route.Weights) > 0 {
+			// 기본값 맵을 공유하지 않도록 복사한 뒤 덮어씀
+			route.Weights = maps.Clone(route.Weights)
+			for subset := range route.Weights {
+				if value := os.Getenv(prefix + "_" + envPrefix(subset) + "_WEIGHT"); value != "" {
+					weight, err := strconv.Atoi(value)
+					if err != nil {
+						weight = -1
+					}
+					route.Weights[subset] = weight
+				}
+			}
+		}
+	}
+}
+
+// validate rejects configurations the gateway cannot serve and resolves the timezone
+func (c *GatewayConfig) validate() error {
+	if errs := validation.IsDNS1123Label(c.Namespace); len(errs) > 0 {
+		return fmt.Errorf("namespace %q: %s", c.Namespace, strings.Join(errs, "; "))
+	}
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone %q: %w", c.Timezone, err)
+	}
+	c.location = location
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535, got %d", c.Port)
+	}
+	if err := c.Server.validate(); err != nil {
+		return fmt.Errorf("server: %w", err)
+	}
+	switch c.OpenAPIValidation {
+	case validationOff, validationRequest, validationFull:
+	default:
+		return fmt.Errorf("openapiValidation must be %s, %s or %s, got %q", validationOff, validationRequest, validationFull, c.OpenAPIValidation)
+	}
+	if len(c.Routes) == 0 {
+		return fmt.Errorf("at least one route is required")
+	}
+
+	services := map[string]bool{}
+	prefixes := map[string]bool{}
+	for i, route := range c.Routes {
+		if errs := validation.IsDNS1123Label(route.Service); len(errs) > 0 {
+			return fmt.Errorf("routes[%d].service %q: %s", i, route.Service, strings.Join(errs, "; "))
+		}
+		if services[route.Service] {
+			return fmt.Errorf("routes[%d]: duplicate service %q", i, route.Service)
+		}
+		services[route.Service] = true
+
+		if !strings.HasPrefix(route.PathPrefix, "/") || !strings.HasSuffix(route.PathPrefix, "/") || route.PathPrefix == "/" {
+			return fmt.Errorf("routes[%d].pathPrefix %q must look like /name/", i, route.PathPrefix)
+		}
+		if prefixes[route.PathPrefix] {
+			return fmt.Errorf("routes[%d]: duplicate pathPrefix %q", i, route.PathPrefix)
+		}
+		prefixes[route.PathPrefix] = true
+		for _, reserved := range reservedPrefixes {
+			if strings.HasPrefix(route.PathPrefix, reserved) || strings.HasPrefix(reserved, route.PathPrefix) {
+				return fmt.Errorf("routes[%d].pathPrefix %q conflicts with gateway endpoint %s", i, route.PathPrefix, reserved)
+			}
+		}
+
+		upstream, err := url.Parse(route.Upstream)
+		if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") || upstream.Host == "" {
+			return fmt.Errorf("routes[%d].upstream %q must be an absolute http(s) URL", i, route.Upstream)
+		}
+		if route.GRPCUpstream != "" {
+			if _, port, err := net.SplitHostPort(route.GRPCUpstream); err != nil || port == "" {
+				return fmt.Errorf("routes[%d].grpcUpstream %q must be host:port", i, route.GRPCUpstream)
+			}
+		}
+		if route.VirtualService != "" {
+			if errs := validation.IsDNS1123Subdomain(route.VirtualService); len(errs) > 0 {
+				return fmt.Errorf("routes[%d].virtualService %q: %s", i, route.VirtualService, strings.Join(errs, "; "))
+			}
+		}
+		if len(route.Weights) > 0 {
+			total := 0
+			for subset, weight := range route.Weights {
+				if weight < 0 || weight > 100 {
+					return fmt.Errorf("routes[%d].weights.%s must be between 0 and 100, got %d", i, subset, weight)
+				}
+				total += weight
+			}
+			if total != 100 {
+				return fmt.Errorf("routes[%d].weights must sum to 100, got %d", i, total)
+			}
+		}
+		if route.DisplayName == "" {
+			c.Routes[i].DisplayName = route.Service
+		}
+		if route.Icon == "" {
+			c.Routes[i].Icon = "📦"
+		}
+	}
+
+	for i, workload := range c.Workloads {
+		if workload.Name == "" {
+			return fmt.Errorf("workloads[%d].name is required", i)
+		}
+		if services[workload.Name] {
+			return fmt.Errorf("workloads[%d]: %q is already configured as a route", i, workload.Name)
+		}
+	}
+	return nil
+}
+
+// validate rejects negative timeouts; zero read/write/idle timeouts disable the limit
+func (s ServerConfig) validate() error {
+	for name, d := range map[string]time.Duration{
+		"readTimeout":       s.ReadTimeout.Duration,
+		"readHeaderTimeout": s.ReadHeaderTimeout.Duration,
+		"writeTimeout":      s.WriteTimeout.Duration,
+		"idleTimeout":       s.IdleTimeout.Duration,
+		"drainDelay":        s.DrainDelay.Duration,
+	} {
+		if d < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	if s.ShutdownTimeout.Duration <= 0 {
+		return fmt.Errorf("shutdownTimeout must be positive")
+	}
+	if s.MaxHeaderBytes < 0 {
+		return fmt.Errorf("maxHeaderBytes must not be negative")
+	}
+	return nil
+}
+
+// loc is the timezone of the UI timestamps; UTC until the config is validated
+func (c *GatewayConfig) loc() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// route returns the route of a service
+func (c *GatewayConfig) route(service string) (RouteConfig, bool) {
+	for _, route := range c.Routes {
+		if route.Service == service {
+			return route, true
+		}
+	}
+	return RouteConfig{}, false
+}
+
+// routeForPath returns the route whose prefix matches the request path
+func (c *GatewayConfig) routeForPath(path string) (RouteConfig, bool) {
+	for _, route := range c.Routes {
+		if strings.HasPrefix(path, route.PathPrefix) {
+			return route, true
+		}
+	}
+	return RouteConfig{}, false
+}
+
+// virtualServiceNames lists the VirtualServices managed by the gateway in route order
+func (c *GatewayConfig) virtualServiceNames() []string {
+	names := []string{}
+	for _, route := range c.Routes {
+		if route.VirtualService != "" {
+			names = append(names, route.VirtualService)
+		}
+	}
+	return names
+}
+
+// weightedServices lists the services whose subset weights the gateway manages, in route order
+func (c *GatewayConfig) weightedServices() []string {
+	services := []string{}
+	for _, route := range c.Routes {
+		if route.VirtualService != "" {
+			services = append(services, route.Service)
+		}
+	}
+	return services
+}
+
+// pathPrefixes lists the routed prefixes, used as the default load mix
+func (c *GatewayConfig) pathPrefixes() []string {
+	prefixes := make([]string, 0, len(c.Routes))
+	for _, route := range c.Routes {
+		prefixes = append(prefixes, route.PathPrefix)
+	}
+	return prefixes
+}
+
+// workload returns the display name, port and icon of a routed service or configured workload
+func (c *GatewayConfig) workload(name string) (WorkloadConfig, bool) {
+	if route, ok := c.route(name); ok {
+		port := ""
+		if upstream, err := url.Parse(route.Upstream); err == nil {
+			port = upstream.Port()
+		}
+		return WorkloadConfig{Name: route.Service, DisplayName: route.DisplayName, Icon: route.Icon, Port: port}, true
+	}
+	for _, workload := range c.Workloads {
+		if workload.Name == name {
+			return workload, true
+		}
+	}
+	return WorkloadConfig{}, false
+}
+
+// workloadForPod matches a pod by its app label, falling back to the pod name
+func (c *GatewayConfig) workloadForPod(app, podName string) (WorkloadConfig, bool) {
+	if workload, ok := c.workload(app); ok {
+		return workload, true
+	}
+	for _, route := range c.Routes {
+		if strings.Contains(podName, route.Service) {
+			return c.workload(route.Service)
+		}
+	}
+	for _, workload := range c.Workloads {
+		if strings.Contains(podName, workload.Name) {
+			return workload, true
+		}
+	}
+	return WorkloadConfig{}, false
+}
+
+// logSummary prints the effective configuration at startup
+func (c *GatewayConfig) logSummary() {
+	slog.Info("Gateway config", "namespace", c.Namespace, "timezone", c.Timezone, "port", c.Port, "openapi_validation", c.OpenAPIValidation, "grpc_transcoding", c.GRPCTranscoding)
+	slog.Info("HTTP server", "read_timeout", c.Server.ReadTimeout.Duration.String(), "write_timeout", c.Server.WriteTimeout.Duration.String(),
+		"idle_timeout", c.Server.IdleTimeout.Duration.String(), "drain_delay", c.Server.DrainDelay.Duration.String(), "shutdown_timeout", c.Server.ShutdownTimeout.Duration.String())
+	for _, route := range c.Routes {
+		slog.Info("Route", "path_prefix", route.PathPrefix, "upstream", route.Upstream, "grpc_upstream", route.GRPCUpstream, "target_service", route.Service, "virtual_service", route.VirtualService)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadGatewayConfigExample(t *testing.T) {
+	cfg, err := loadGatewayConfig("gateway-config.example.yaml")
+	if err != nil {
+		t.Fatalf("loadGatewayConfig(example) error = %v", err)
+	}
+	if len(cfg.Routes) == 0 || cfg.location == nil {
+		t.Errorf("config = %+v, want routes and a resolved timezone", cfg)
+	}
+}
+
+func TestLoadGatewayConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "routes replace the defaults",
+			yaml: `
+namespace: theater-dev
+server:
+  writeTimeout: 5s
+routes:
+- service: movie-service
+  pathPrefix: /movies/
+  upstream: http://movie-service:8082
+  virtualService: movie-service-vs
+  weights: {ctx1: 100, ctx2: 0}
+`,
+		},
+		{name: "unknown field", yaml: "namespaces: theater-dev\n", wantErr: "failed to parse config"},
+		{name: "invalid weights", yaml: "routes:\n- service: movie-service\n  pathPrefix: /movies/\n  upstream: http://movie:8082\n  weights: {ctx1: 60, ctx2: 60}\n", wantErr: "routes[0].weights must sum to 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "gateway-config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := loadGatewayConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadGatewayConfig() error = %v", err)
+			}
+			if cfg.Namespace != "theater-dev" || len(cfg.Routes) != 1 || cfg.Routes[0].DisplayName != "movie-service" {
+				t.Errorf("config = %+v, want the file's namespace and single route", cfg)
+			}
+			// 파일에 없는 서버 설정은 기본값 유지
+			if cfg.Server.WriteTimeout.Duration != 5*time.Second || cfg.Server.ReadTimeout.Duration != 30*time.Second {
+				t.Errorf("server = %+v, want writeTimeout 5s and the default readTimeout", cfg.Server)
+			}
+		})
+	}
+}
+
+func TestGatewayConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *GatewayConfig)
+		wantErr string
+	}{
+		{name: "defaults", mutate: func(c *GatewayConfig) {}},
+		{name: "invalid namespace", mutate: func(c *GatewayConfig) { c.Namespace = "Theater_MSA" }, wantErr: "namespace"},
+		{name: "unknown timezone", mutate: func(c *GatewayConfig) { c.Timezone = "Mars/Olympus" }, wantErr: "timezone"},
+		{name: "port out of range", mutate: func(c *GatewayConfig) { c.Port = 70000 }, wantErr: "port"},
+		{name: "negative timeout", mutate: func(c *GatewayConfig) { c.Server.ReadTimeout.Duration = -1 }, wantErr: "readTimeout must not be negative"},
+		{name: "no shutdown timeout", mutate: func(c *GatewayConfig) { c.Server.ShutdownTimeout.Duration = 0 }, wantErr: "shutdownTimeout"},
+		{name: "openapi validation mode", mutate: func(c *GatewayConfig) { c.OpenAPIValidation = "strict" }, wantErr: "openapiValidation"},
+		{name: "no routes", mutate: func(c *GatewayConfig) { c.Routes = nil }, wantErr: "at least one route"},
+		{name: "duplicate service", mutate: func(c *GatewayConfig) { c.Routes[1].Service = "user-service" }, wantErr: "duplicate service"},
+		{name: "duplicate prefix", mutate: func(c *GatewayConfig) { c.Routes[1].PathPrefix = "/users/" }, wantErr: "duplicate pathPrefix"},
+		{name: "prefix without slashes", mutate: func(c *GatewayConfig) { c.Routes[0].PathPrefix = "users" }, wantErr: "must look like /name/"},
+		{name: "reserved prefix", mutate: func(c *GatewayConfig) { c.Routes[0].PathPrefix = "/admin/" }, wantErr: "conflicts with gateway endpoint"},
+		{name: "relative upstream", mutate: func(c *GatewayConfig) { c.Routes[0].Upstream = "user-service:8081" }, wantErr: "absolute http(s) URL"},
+		{name: "grpc upstream without port", mutate: func(c *GatewayConfig) { c.Routes[0].GRPCUpstream = "user-service" }, wantErr: "host:port"},
+		{name: "invalid virtual service", mutate: func(c *GatewayConfig) { c.Routes[0].VirtualService = "User VS" }, wantErr: "virtualService"},
+		{name: "weight above 100", mutate: func(c *GatewayConfig) { c.Routes[0].Weights = map[string]int{"ctx1": 150, "ctx2": -50} }, wantErr: "between 0 and 100"},
+		{name: "weights sum", mutate: func(c *GatewayConfig) { c.Routes[0].Weights = map[string]int{"ctx1": 70, "ctx2": 20} }, wantErr: "must sum to 100"},
+		{name: "no weights", mutate: func(c *GatewayConfig) { c.Routes[0].Weights = nil }},
+		{name: "workload without name", mutate: func(c *GatewayConfig) { c.Workloads[0].Name = "" }, wantErr: "workloads[0].name"},
+		{name: "workload shadows route", mutate: func(c *GatewayConfig) { c.Workloads[0].Name = "movie-service" }, wantErr: "already configured as a route"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultGatewayConfig()
+			tt.mutate(cfg)
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGatewayConfigEnv(t *testing.T) {
+	t.Setenv("GATEWAY_PORT", "9090")
+	t.Setenv("MOVIE_SERVICE_UPSTREAM", "http://movie.local:8082")
+	t.Setenv("MOVIE_SERVICE_CTX1_WEIGHT", "90")
+	t.Setenv("MOVIE_SERVICE_CTX2_WEIGHT", "10")
+	t.Setenv("HTTP_READ_TIMEOUT", "15s")
+	t.Setenv("SHUTDOWN_DRAIN_SECONDS", "2")
+
+	cfg, err := loadGatewayConfig("")
+	if err != nil {
+		t.Fatalf("loadGatewayConfig() error = %v", err)
+	}
+	route, _ := cfg.route("movie-service")
+	if cfg.Port != 9090 || route.Upstream != "http://movie.local:8082" || cfg.Server.ReadTimeout.Duration != 15*time.Second || cfg.Server.DrainDelay.Duration != 2*time.Second {
+		t.Errorf("config = %+v, want the environment overrides", cfg)
+	}
+	if want := map[string]int{"ctx1": 90, "ctx2": 10}; !reflect.DeepEqual(route.Weights, want) {
+		t.Errorf("movie-service weights = %v, want %v", route.Weights, want)
+	}
+	if defaults, _ := defaultGatewayConfig().route("movie-service"); defaults.Weights["ctx1"] != 30 {
+		t.Errorf("default weights changed to %v", defaults.Weights)
+	}
+
+	// 잘못된 값은 기본값으로 넘어가지 않고 거부
+	for key, value := range map[string]string{
+		"GATEWAY_PORT":              "http",
+		"HTTP_WRITE_TIMEOUT":        "soon",
+		"MOVIE_SERVICE_CTX1_WEIGHT": "most",
+		"SHUTDOWN_DRAIN_SECONDS":    "-",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := loadGatewayConfig(""); err == nil {
+				t.Errorf("%s=%q: loadGatewayConfig() error = nil, want invalid config", key, value)
+			}
+		})
+	}
+}
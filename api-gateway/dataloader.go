@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loaderWait is how long a batch collects keys from the fields resolving concurrently
+const loaderWait = 2 * time.Millisecond
+
+// batchLoader is a per-request dataloader: keys requested by concurrently resolving fields
+// during loaderWait are fetched with one call, and every key is fetched at most once per request
+type batchLoader[V any] struct {
+	ctx   context.Context // 요청 컨텍스트, 업스트림 호출 span 이 GraphQL 요청 trace 에 포함
+	fetch func(ctx context.Context, keys []string) (map[string]V, error)
+
+	mu      sync.Mutex
+	pending *loaderBatch[V]
+	batches map[string]*loaderBatch[V] // key → 그 key 를 조회한(조회 중인) batch
+}
+
+// loaderBatch is one fetch; values and err are set before done is closed
+type loaderBatch[V any] struct {
+	keys   []string
+	done   chan struct{}
+	values map[string]V
+	err    error
+}
+
+func newBatchLoader[V any](ctx context.Context, fetch func(ctx context.Context, keys []string) (map[string]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{ctx: ctx, fetch: fetch, batches: map[string]*loaderBatch[V]{}}
+}
+
+// load returns the value of key; found is false when the upstream does not know the key
+func (l *batchLoader[V]) load(ctx context.Context, key string) (value V, found bool, err error) {
+	l.mu.Lock()
+	batch, ok := l.batches[key]
+	if !ok {
+		if l.pending == nil {
+			l.pending = &loaderBatch[V]{done: make(chan struct{})}
+			go l.dispatch(l.pending)
+		}
+		batch = l.pending
+		batch.keys = append(batch.keys, key)
+		l.batches[key] = batch
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return value, false, ctx.Err()
+	}
+	value, found = batch.values[key]
+	return value, found, batch.err
+}
+
+// dispatch closes the batch to new keys after loaderWait and fetches it
+func (l *batchLoader[V]) dispatch(batch *loaderBatch[V]) {
+	time.Sleep(loaderWait)
+	l.mu.Lock()
+	l.pending = nil
+	keys := batch.keys
+	l.mu.Unlock()
+
+	batch.values, batch.err = l.fetch(l.ctx, keys)
+	close(batch.done)
+}
+
+// fetchEach fetches keys one by one in parallel, for upstreams without a batch endpoint;
+// the loader still removes duplicate keys within the request
+func fetchEach[V any](fetchOne func(ctx context.Context, key string) (V, bool, error)) func(context.Context, []string) (map[string]V, error) {
+	return func(ctx context.Context, keys []string) (map[string]V, error) {
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			values   = make(map[string]V, len(keys))
+			firstErr error
+		)
+		for _, key := range keys {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				value, found, err := fetchOne(ctx, key)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					return
+				}
+				if found {
+					values[key] = value
+				}
+			}(key)
+		}
+		wg.Wait()
+		return values, firstErr
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// recordingFetch returns value "v-<key>" for every key except "missing" and records each batch
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (f *recordingFetch) fetch(_ context.Context, keys []string) (map[string]string, error) {
+	f.mu.Lock()
+	batch := append([]string{}, keys...)
+	sort.Strings(batch)
+	f.batches = append(f.batches, batch)
+	f.mu.Unlock()
+
+	values := map[string]string{}
+	for _, key := range keys {
+		if key != "missing" {
+			values[key] = "v-" + key
+		}
+	}
+	return values, f.err
+}
+
+// loadConcurrently loads every key from its own goroutine, like sibling GraphQL fields
+func loadConcurrently(loader *batchLoader[string], keys []string) ([]string, []bool, []error) {
+	values := make([]string, len(keys))
+	found := make([]bool, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			values[i], found[i], errs[i] = loader.load(context.Background(), key)
+		}(i, key)
+	}
+	wg.Wait()
+	return values, found, errs
+}
+
+func TestBatchLoaderDeduplicates(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        []string
+		wantBatch   []string
+		wantValues  []string
+		wantMissing []bool
+	}{
+		{
+			name:        "distinct keys in one batch",
+			keys:        []string{"m1", "m2", "m3"},
+			wantBatch:   []string{"m1", "m2", "m3"},
+			wantValues:  []string{"v-m1", "v-m2", "v-m3"},
+			wantMissing: []bool{false, false, false},
+		},
+		{
+			name:        "duplicate keys fetched once",
+			keys:        []string{"u1", "u2", "u1", "u1", "u2"},
+			wantBatch:   []string{"u1", "u2"},
+			wantValues:  []string{"v-u1", "v-u2", "v-u1", "v-u1", "v-u2"},
+			wantMissing: []bool{false, false, false, false, false},
+		},
+		{
+			name:        "unknown key is not found",
+			keys:        []string{"m1", "missing", "missing"},
+			wantBatch:   []string{"m1", "missing"},
+			wantValues:  []string{"v-m1", "", ""},
+			wantMissing: []bool{false, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetch := &recordingFetch{}
+			loader := newBatchLoader(context.Background(), fetch.fetch)
+
+			values, found, errs := loadConcurrently(loader, tt.keys)
+			for i := range tt.keys {
+				if errs[i] != nil {
+					t.Fatalf("load(%q) error = %v", tt.keys[i], errs[i])
+				}
+				if found[i] == tt.wantMissing[i] {
+					t.Errorf("load(%q) found = %v, want %v", tt.keys[i], found[i], !tt.wantMissing[i])
+				}
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("values = %v, want %v", values, tt.wantValues)
+			}
+			if want := [][]string{tt.wantBatch}; !reflect.DeepEqual(fetch.batches, want) {
+				t.Errorf("batches = %v, want %v", fetch.batches, want)
+			}
+		})
+	}
+}
+
+func TestBatchLoaderCachesAcrossBatches(t *testing.T) {
+	fetch := &recordingFetch{}
+	loader := newBatchLoader(context.Background(), fetch.fetch)
+
+	loadConcurrently(loader, []string{"m1", "m2"})
+	// 이전 batch 가 끝난 뒤의 조회: 이미 조회한 key 는 다시 호출하지 않음
+	values, _, _ := loadConcurrently(loader, []string{"m2", "m3"})
+
+	if want := []string{"v-m2", "v-m3"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if want := [][]string{{"m1", "m2"}, {"m3"}}; !reflect.DeepEqual(fetch.batches, want) {
+		t.Errorf("batches = %v, want %v", fetch.batches, want)
+	}
+}
+
+func TestBatchLoaderSharesError(t *testing.T) {
+	upstreamDown := errors.New("upstream down")
+	fetch := &recordingFetch{err: upstreamDown}
+	loader := newBatchLoader(context.Background(), fetch.fetch)
+
+	_, _, errs := loadConcurrently(loader, []string{"m1", "m2", "m1"})
+	for i, err := range errs {
+		if !errors.Is(err, upstreamDown) {
+			t.Errorf("load %d error = %v, want %v", i, err, upstreamDown)
+		}
+	}
+	if len(fetch.batches) != 1 {
+		t.Errorf("fetched %d batches, want 1", len(fetch.batches))
+	}
+}
+
+func TestFetchEach(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	fetch := fetchEach(func(_ context.Context, key string) (string, bool, error) {
+		mu.Lock()
+		calls[key]++
+		mu.Unlock()
+		return "v-" + key, key != "missing", nil
+	})
+
+	values, err := fetch(context.Background(), []string{"u1", "u2", "missing"})
+	if err != nil {
+		t.Fatalf("fetchEach error = %v", err)
+	}
+	if want := map[string]string{"u1": "v-u1", "u2": "v-u2"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if want := map[string]int{"u1": 1, "u2": 1, "missing": 1}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
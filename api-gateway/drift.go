@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// EventTypeDrift is published when a service starts or stops drifting
+const EventTypeDrift = "drift"
+
+// DriftConfig controls the sensitivity of the drift detector
+type DriftConfig struct {
+	Window           time.Duration
+	Alpha            float64 // 카이제곱 검정 유의수준
+	ThresholdPercent float64 // 설정 대비 관측 비율 차이 허용치 (%p)
+	MinSamples       int
+}
+
+// ServiceDrift is the comparison result for one service
+type ServiceDrift struct {
+	Service         string             `json:"service"`
+	Configured      map[string]int     `json:"configured"`
+	ObservedCounts  map[string]int     `json:"observedCounts"`
+	ObservedPercent map[string]float64 `json:"observedPercent"`
+	Samples         int                `json:"samples"`
+	Ignored         int                `json:"ignored"` // 알 수 없는 클러스터로 기록된 요청 수
+	ChiSquare       float64            `json:"chiSquare"`
+	PValue          float64            `json:"pValue"`
+	MaxDeviation    float64            `json:"maxDeviationPercent"`
+	Significant     bool               `json:"significant"`
+	Drifting        bool               `json:"drifting"`
+	EnoughSamples   bool               `json:"enoughSamples"`
+	Summary         string             `json:"summary"`
+}
+
+// DriftReport is the /traffic-drift response body
+type DriftReport struct {
+	GeneratedAt      string         `json:"generatedAt"`
+	WindowSeconds    int            `json:"windowSeconds"`
+	Alpha            float64        `json:"alpha"`
+	ThresholdPercent float64        `json:"thresholdPercent"`
+	MinSamples       int            `json:"minSamples"`
+	Services         []ServiceDrift `json:"services"`
+}
+
+var driftConfig = DriftConfig{
+	Window:           time.Duration(getEnvInt("DRIFT_WINDOW_SECONDS", 300)) * time.Second,
+	Alpha:            getEnvFloat("DRIFT_ALPHA", 0.01),
+	ThresholdPercent: getEnvFloat("DRIFT_THRESHOLD_PERCENT", 10),
+	MinSamples:       getEnvInt("DRIFT_MIN_SAMPLES", 20),
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// evaluateDrift compares the configured weights of every weighted service with the observed distribution
+// in the window; subsets are compared with the X-Service-Cluster of the responses, so they are named after the clusters
+func evaluateDrift(weights subsetWeights, records []TrafficRecord, cfg DriftConfig) DriftReport {
+	report := DriftReport{
+		GeneratedAt:      time.Now().Format(time.RFC3339),
+		WindowSeconds:    int(cfg.Window.Seconds()),
+		Alpha:            cfg.Alpha,
+		ThresholdPercent: cfg.ThresholdPercent,
+		MinSamples:       cfg.MinSamples,
+	}
+
+	services := gatewayConfig.weightedServices()
+	counts := map[string]map[string]int{}
+	for _, service := range services {
+		counts[service] = map[string]int{}
+	}
+	for _, record := range records {
+		if byCluster, ok := counts[record.Service]; ok {
+			byCluster[record.Cluster]++
+		}
+	}
+
+	for _, service := range services {
+		report.Services = append(report.Services, compareDistribution(service, weights[service], counts[service], cfg))
+	}
+	return report
+}
+
+// compareDistribution runs a chi-square goodness-of-fit test for a single service over the configured subsets
+func compareDistribution(service string, configured map[string]int, counts map[string]int, cfg DriftConfig) ServiceDrift {
+	if configured == nil {
+		configured = map[string]int{}
+	}
+	result := ServiceDrift{
+		Service:         service,
+		Configured:      configured,
+		ObservedCounts:  map[string]int{},
+		ObservedPercent: map[string]float64{},
+		PValue:          1,
+	}
+
+	clusters := slices.Sorted(maps.Keys(configured))
+	totalWeight := 0
+	for _, cluster := range clusters {
+		totalWeight += configured[cluster]
+		result.ObservedCounts[cluster] = counts[cluster]
+		result.Samples += counts[cluster]
+	}
+	for cluster, count := range counts {
+		if _, ok := configured[cluster]; !ok {
+			result.Ignored += count
+		}
+	}
+
+	result.EnoughSamples = result.Samples >= cfg.MinSamples
+	if len(clusters) == 0 {
+		result.Summary = "no weights configured"
+		return result
+	}
+	if result.Samples == 0 || totalWeight == 0 {
+		result.Summary = fmt.Sprintf("configured %s, no observations", formatSplit(configured, clusters, totalWeight))
+		return result
+	}
+
+	degrees := -1
+	zeroWeightHit := false
+	for _, cluster := range clusters {
+		observed := float64(counts[cluster])
+		expected := float64(result.Samples) * float64(configured[cluster]) / float64(totalWeight)
+		observedPercent := observed / float64(result.Samples) * 100
+		result.ObservedPercent[cluster] = math.Round(observedPercent*10) / 10
+
+		deviation := math.Abs(observedPercent - float64(configured[cluster])*100/float64(totalWeight))
+		result.MaxDeviation = math.Max(result.MaxDeviation, math.Round(deviation*10)/10)
+
+		if expected == 0 {
+			// 가중치 0인 클러스터로 요청이 간 경우 설정과 명백히 불일치
+			if observed > 0 {
+				zeroWeightHit = true
+			}
+			continue
+		}
+		degrees++
+		result.ChiSquare += (observed - expected) * (observed - expected) / expected
+	}
+
+	switch {
+	case zeroWeightHit:
+		result.PValue = 0
+	case degrees > 0:
+		result.PValue = chiSquareSurvival(result.ChiSquare, float64(degrees))
+	}
+
+	result.Significant = result.PValue < cfg.Alpha
+	result.Drifting = result.EnoughSamples && result.Significant && result.MaxDeviation >= cfg.ThresholdPercent
+
+	verdict := "not significant"
+	if result.Drifting {
+		verdict = "significant"
+	} else if !result.EnoughSamples {
+		verdict = fmt.Sprintf("insufficient samples (%d/%d)", result.Samples, cfg.MinSamples)
+	}
+	observed := make([]string, len(clusters))
+	for i, cluster := range clusters {
+		observed[i] = fmt.Sprintf("%.0f", result.ObservedPercent[cluster])
+	}
+	result.Summary = fmt.Sprintf("configured %s, observed %s, %s", formatSplit(configured, clusters, totalWeight), strings.Join(observed, "/"), verdict)
+	return result
+}
+
+// formatSplit renders the configured percentages in subset order, e.g. "70/30"
+func formatSplit(configured map[string]int, clusters []string, total int) string {
+	split := make([]string, len(clusters))
+	for i, cluster := range clusters {
+		percent := 0
+		if total > 0 {
+			percent = configured[cluster] * 100 / total
+		}
+		split[i] = strconv.Itoa(percent)
+	}
+	return strings.Join(split, "/")
+}
+
+// chiSquareSurvival returns P(X >= x) for a chi-square distribution with k degrees of freedom
+func chiSquareSurvival(x, k float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return upperIncompleteGammaRatio(k/2, x/2)
+}
+
+// upperIncompleteGammaRatio computes the regularized upper incomplete gamma Q(a, x)
+func upperIncompleteGammaRatio(a, x float64) float64 {
+	lgammaA, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgammaA)
+
+	if x < a+1 {
+		// 급수 전개
+		sum := 1 / a
+		term := sum
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-14 {
+				break
+			}
+		}
+		return 1 - prefix*sum
+	}
+
+	// 연분수 전개 (Lentz 방법)
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 500; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-14 {
+			break
+		}
+	}
+	return prefix * h
+}
+
+// driftDetector periodically evaluates drift and publishes state changes
+type driftDetector struct {
+	mu       sync.Mutex
+	drifting map[string]bool
+}
+
+var drift = &driftDetector{drifting: map[string]bool{}}
+
+// run evaluates drift at every interval until the process exits
+func (d *driftDetector) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		report := evaluateDrift(getVirtualServiceWeights(), windowRecords(driftConfig.Window), driftConfig)
+
+		d.mu.Lock()
+		for _, service := range report.Services {
+			if d.drifting[service.Service] != service.Drifting {
+				d.drifting[service.Service] = service.Drifting
+				slog.Warn("Traffic drift state changed", "target_service", service.Service, "drifting", service.Drifting, "summary", service.Summary)
+				events.publish(EventTypeDrift, service)
+			}
+		}
+		d.mu.Unlock()
+	}
+}
+
+// startDriftDetector launches the background comparator
+func startDriftDetector() {
+	interval := getEnvSeconds("DRIFT_CHECK_INTERVAL_SECONDS", 10)
+	go drift.run(interval)
+}
+
+// getTrafficDrift returns the configured-versus-observed comparison for every service
+func getTrafficDrift(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	cfg := driftConfig
+	if value := r.URL.Query().Get("window"); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid window duration")
+			return
+		}
+		cfg.Window = window
+	}
+
+	report := evaluateDrift(getVirtualServiceWeights(), windowRecords(cfg.Window), cfg)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	networkingv1 "istio.io/client-go/pkg/apis/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestChiSquareSurvival(t *testing.T) {
+	// 기대값: 카이제곱 분포표의 임계값과 자유도 1, 2, 4 의 닫힌 형태
+	tests := []struct {
+		name string
+		x, k float64
+		want float64
+	}{
+		{"zero statistic", 0, 1, 1},
+		{"negative statistic", -1, 3, 1},
+		{"critical 0.05, k=1", 3.841458820694124, 1, 0.05},
+		{"critical 0.01, k=1", 6.634896601021214, 1, 0.01},
+		{"critical 0.05, k=2", 5.991464547107979, 2, 0.05},
+		{"critical 0.05, k=5", 11.070497693516351, 5, 0.05},
+		{"critical 0.05, k=10", 18.307038053275146, 10, 0.05},
+		{"critical 0.001, k=3", 16.26623619623813, 3, 0.001},
+		{"k=1 erfc", 1, 1, math.Erfc(math.Sqrt(0.5))},
+		{"k=2 exponential", 2, 2, math.Exp(-1)},
+		{"k=4 closed form", 0.5, 4, math.Exp(-0.25) * 1.25},
+		{"far tail, k=1", 100, 1, math.Erfc(math.Sqrt(50))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chiSquareSurvival(tt.x, tt.k)
+			if math.Abs(got-tt.want) > 1e-6*tt.want {
+				t.Errorf("chiSquareSurvival(%v, %v) = %.12g, want %.12g", tt.x, tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareDistribution(t *testing.T) {
+	cfg := DriftConfig{Alpha: 0.01, ThresholdPercent: 10, MinSamples: 20}
+	tests := []struct {
+		name            string
+		configured      map[string]int
+		counts          map[string]int
+		wantSignificant bool
+		wantDrifting    bool
+		wantEnough      bool
+		wantIgnored     int
+		wantSummary     string
+	}{
+		{
+			name:            "configured 50/50, observed 100/0",
+			configured:      map[string]int{"ctx1": 50, "ctx2": 50},
+			counts:          map[string]int{"ctx1": 100},
+			wantSignificant: true,
+			wantDrifting:    true,
+			wantEnough:      true,
+			wantSummary:     "configured 50/50, observed 100/0, significant",
+		},
+		{
+			name:        "matches the configuration",
+			configured:  map[string]int{"ctx1": 70, "ctx2": 30},
+			counts:      map[string]int{"ctx1": 72, "ctx2": 28},
+			wantEnough:  true,
+			wantSummary: "configured 70/30, observed 72/28, not significant",
+		},
+		{
+			name:            "insufficient samples",
+			configured:      map[string]int{"ctx1": 50, "ctx2": 50},
+			counts:          map[string]int{"ctx1": 10},
+			wantSignificant: true,
+			wantSummary:     "configured 50/50, observed 100/0, insufficient samples (10/20)",
+		},
+		{
+			name:            "zero-weight subset receives traffic",
+			configured:      map[string]int{"ctx1": 100, "ctx2": 0},
+			counts:          map[string]int{"ctx1": 95, "ctx2": 5},
+			wantSignificant: true,
+			wantEnough:      true,
+			// 편차 5%p 는 허용치(10%p) 미만이므로 유의하지만 drift 로 보지 않음
+			wantSummary: "configured 100/0, observed 95/5, not significant",
+		},
+		{
+			name:            "zero-weight subset above the threshold",
+			configured:      map[string]int{"ctx1": 100, "ctx2": 0},
+			counts:          map[string]int{"ctx1": 50, "ctx2": 50},
+			wantSignificant: true,
+			wantDrifting:    true,
+			wantEnough:      true,
+			wantSummary:     "configured 100/0, observed 50/50, significant",
+		},
+		{
+			name:            "three subsets",
+			configured:      map[string]int{"ctx1": 50, "ctx2": 25, "ctx3": 25},
+			counts:          map[string]int{"ctx1": 20, "ctx2": 20, "ctx3": 60},
+			wantSignificant: true,
+			wantDrifting:    true,
+			wantEnough:      true,
+			wantSummary:     "configured 50/25/25, observed 20/20/60, significant",
+		},
+		{
+			name:        "unknown clusters are ignored",
+			configured:  map[string]int{"ctx1": 50, "ctx2": 50},
+			counts:      map[string]int{"ctx1": 50, "ctx2": 50, "unknown": 7},
+			wantEnough:  true,
+			wantIgnored: 7,
+			wantSummary: "configured 50/50, observed 50/50, not significant",
+		},
+		{
+			name:        "no observations",
+			configured:  map[string]int{"ctx1": 50, "ctx2": 50},
+			counts:      map[string]int{},
+			wantSummary: "configured 50/50, no observations",
+		},
+		{
+			name:        "no weights configured",
+			counts:      map[string]int{"ctx1": 30},
+			wantIgnored: 30,
+			wantSummary: "no weights configured",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareDistribution("movie-service", tt.configured, tt.counts, cfg)
+			if got.Significant != tt.wantSignificant || got.Drifting != tt.wantDrifting || got.EnoughSamples != tt.wantEnough {
+				t.Errorf("significant, drifting, enough = %v, %v, %v; want %v, %v, %v (p=%g, deviation=%v)",
+					got.Significant, got.Drifting, got.EnoughSamples, tt.wantSignificant, tt.wantDrifting, tt.wantEnough, got.PValue, got.MaxDeviation)
+			}
+			if got.Ignored != tt.wantIgnored {
+				t.Errorf("ignored = %d, want %d", got.Ignored, tt.wantIgnored)
+			}
+			if got.Summary != tt.wantSummary {
+				t.Errorf("summary = %q, want %q", got.Summary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestEvaluateDrift(t *testing.T) {
+	previous := gatewayConfig
+	gatewayConfig = defaultGatewayConfig()
+	t.Cleanup(func() { gatewayConfig = previous })
+
+	records := []TrafficRecord{}
+	for i := 0; i < 40; i++ {
+		records = append(records,
+			TrafficRecord{Service: "movie-service", Cluster: "ctx1"},
+			TrafficRecord{Service: "user-service", Cluster: "ctx1"},
+			TrafficRecord{Service: "graphql", Cluster: "ctx1"},
+		)
+	}
+	weights := subsetWeights{
+		"user-service":  {"ctx1": 100, "ctx2": 0},
+		"movie-service": {"ctx1": 50, "ctx2": 50},
+	}
+	report := evaluateDrift(weights, records, DriftConfig{Alpha: 0.01, ThresholdPercent: 10, MinSamples: 20})
+
+	var services []string
+	drifting := map[string]bool{}
+	for _, service := range report.Services {
+		services = append(services, service.Service)
+		drifting[service.Service] = service.Drifting
+	}
+	// 설정된 라우트 순서, 가중치를 모르는 서비스도 포함
+	if want := []string{"user-service", "movie-service", "booking-service"}; !reflect.DeepEqual(services, want) {
+		t.Fatalf("services = %v, want %v", services, want)
+	}
+	if drifting["user-service"] || !drifting["movie-service"] || drifting["booking-service"] {
+		t.Errorf("drifting = %v, want only movie-service", drifting)
+	}
+}
+
+func TestGetVirtualServiceWeights(t *testing.T) {
+	useFakeIstio(t)
+	dr := &networkingv1.DestinationRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "movie-service-dr", Namespace: "theater-msa"},
+		Spec: networkingv1alpha3.DestinationRule{
+			Host:    "movie-service.theater-msa.svc.cluster.local",
+			Subsets: []*networkingv1alpha3.Subset{{Name: "ctx1"}, {Name: "ctx2"}, {Name: "ctx3"}},
+		},
+	}
+	if _, err := istioClient.NetworkingV1().DestinationRules("theater-msa").Create(context.Background(), dr, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := subsetWeights{
+		"user-service": {"ctx1": 50, "ctx2": 50},
+		// DestinationRule 에만 있는 subset 은 가중치 0 으로 비교 대상에 포함
+		"movie-service": {"ctx1": 50, "ctx2": 50, "ctx3": 0},
+		// VirtualService 가 없으면 설정된 가중치를 그대로 사용
+		"booking-service": {"ctx1": 50, "ctx2": 50},
+	}
+	if got := getVirtualServiceWeights(); !reflect.DeepEqual(got, want) {
+		t.Errorf("getVirtualServiceWeights() = %v, want %v", got, want)
+	}
+}
+
+func TestGetVirtualServiceWeightsWithoutIstio(t *testing.T) {
+	previousClient, previousConfig := istioClient, gatewayConfig
+	istioClient, gatewayConfig = nil, defaultGatewayConfig()
+	t.Cleanup(func() { istioClient, gatewayConfig = previousClient, previousConfig })
+
+	got := getVirtualServiceWeights()
+	if want := map[string]int{"ctx1": 30, "ctx2": 70}; !reflect.DeepEqual(got["movie-service"], want) {
+		t.Errorf("movie-service weights = %v, want configured %v", got["movie-service"], want)
+	}
+	// 반환값을 바꿔도 설정은 그대로
+	got["movie-service"]["ctx1"] = 0
+	if gatewayConfig.Routes[1].Weights["ctx1"] != 30 {
+		t.Error("getVirtualServiceWeights() returned the configured map itself")
+	}
+}
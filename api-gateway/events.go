@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// Event types published on the live event stream
+const (
+	EventTypeRouting   = "routing"
+	EventTypeWeights   = "weights"
+	EventTypePodStatus = "pod-status"
+)
+
+// Event is a single message delivered to /events subscribers
+type Event struct {
+	Type      string      `json:"type"`
+	Timestamp string      `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// RoutingEvent describes one proxied request and where it was served
+type RoutingEvent struct {
+	Service    string  `json:"service"`
+	Cluster    string  `json:"cluster"`
+	Pod        string  `json:"pod"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	StatusCode int     `json:"statusCode"`
+	LatencyMs  float64 `json:"latencyMs"`
+	Timestamp  string  `json:"timestamp"`
+}
+
+// WeightChangeEvent carries the previous and current VirtualService weights by service and subset
+type WeightChangeEvent struct {
+	Previous subsetWeights `json:"previous"`
+	Current  subsetWeights `json:"current"`
+}
+
+// PodStatusEvent describes a pod whose status changed between two polls
+type PodStatusEvent struct {
+	Service        string `json:"service"`
+	Cluster        string `json:"cluster"`
+	PodName        string `json:"podName"`
+	PreviousStatus string `json:"previousStatus"`
+	Status         string `json:"status"`
+}
+
+// eventBroker fans out events to all connected subscribers
+type eventBroker struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+var events = newEventBroker()
+
+// shutdownStarted is closed when the drain ends so long-lived event streams return and do not hold up Shutdown
+var shutdownStarted = make(chan struct{})
+
+const subscriberBufferSize = 64
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{subscribers: make(map[chan Event]struct{})}
+}
+
+func (b *eventBroker) subscribe() chan Event {
+	ch := make(chan Event, subscriberBufferSize)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *eventBroker) unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	delete(b.subscribers, ch)
+	b.mu.Unlock()
+}
+
+// publish delivers the event without blocking; slow subscribers drop events
+func (b *eventBroker) publish(eventType string, data interface{}) {
+	event := Event{
+		Type:      eventType,
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Data:      data,
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// serveEventStream streams events to the client as Server-Sent Events
+func serveEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeInternal, "Streaming unsupported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	// 스트림은 서버 WriteTimeout 대상에서 제외
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	ch := events.subscribe()
+	defer events.unsubscribe(ch)
+
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-shutdownStarted:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event := <-ch:
+			payload, err := json.Marshal(event)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "Failed to marshal event", "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+			flusher.Flush()
+		}
+	}
+}
+
+// eventWebSocketServer streams the same events over a WebSocket connection.
+// Handshake is left nil so clients without an Origin header (CLI tools) are accepted.
+var eventWebSocketServer = websocket.Server{
+	Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		ch := events.subscribe()
+		defer events.unsubscribe(ch)
+
+		// 클라이언트 종료 감지를 위해 수신 메시지는 읽고 버린다
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var discard string
+			for {
+				if err := websocket.Message.Receive(conn, &discard); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case <-shutdownStarted:
+				return
+			case event := <-ch:
+				if err := websocket.JSON.Send(conn, event); err != nil {
+					return
+				}
+			}
+		}
+	},
+}
+
+// watchTrafficWeights polls VirtualService weights and publishes changes
+func watchTrafficWeights(interval time.Duration) {
+	previous := getVirtualServiceWeights()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		current := getVirtualServiceWeights()
+		if !reflect.DeepEqual(current, previous) {
+			slog.Info("Traffic weights changed", "previous", previous, "current", current)
+			events.publish(EventTypeWeights, WeightChangeEvent{Previous: previous, Current: current})
+			previous = current
+		}
+	}
+}
+
+// watchPodStatus polls pods in every cluster and publishes status transitions
+func watchPodStatus(interval time.Duration) {
+	var previous map[string]DeploymentInfo
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		statuses, err := collectDeploymentStatus(context.TODO())
+		if statuses == nil {
+			slog.Warn("Failed to list pods for status watch", "error", err)
+			continue
+		}
+
+		current := map[string]DeploymentInfo{}
+		for _, status := range statuses {
+			prefix := status.Cluster + "/"
+			if !status.Reachable {
+				// 연결할 수 없는 클러스터의 파드는 삭제로 보지 않고 이전 상태 유지
+				for key, old := range previous {
+					if strings.HasPrefix(key, prefix) {
+						current[key] = old
+					}
+				}
+				continue
+			}
+
+			for _, info := range status.Deployments {
+				key := prefix + info.PodName
+				current[key] = info
+
+				// 최초 조회 결과는 기준값으로만 사용
+				if previous == nil {
+					continue
+				}
+				if old, ok := previous[key]; !ok || old.Status != info.Status {
+					events.publish(EventTypePodStatus, PodStatusEvent{
+						Service:        info.Service,
+						Cluster:        info.Cluster,
+						PodName:        info.PodName,
+						PreviousStatus: old.Status,
+						Status:         info.Status,
+					})
+				}
+			}
+		}
+
+		for key, old := range previous {
+			if _, ok := current[key]; !ok {
+				events.publish(EventTypePodStatus, PodStatusEvent{
+					Service:        old.Service,
+					Cluster:        old.Cluster,
+					PodName:        old.PodName,
+					PreviousStatus: old.Status,
+					Status:         "Deleted",
+				})
+			}
+		}
+
+		previous = current
+	}
+}
+
+// startEventWatchers launches the background pollers that feed weight and pod events
+func startEventWatchers() {
+	interval := getEnvSeconds("EVENT_POLL_INTERVAL_SECONDS", 5)
+
+	if istioClient != nil {
+		go watchTrafficWeights(interval)
+	}
+	if kubernetesClient != nil {
+		go watchPodStatus(interval)
+	}
+}
+
+// getEnvSeconds reads a positive number of seconds; time.NewTicker panics on zero or negative
+// intervals, so those fall back to the default
+func getEnvSeconds(key string, defaultSeconds int) time.Duration {
+	seconds := getEnvInt(key, defaultSeconds)
+	if seconds <= 0 {
+		slog.Warn("Ignoring non-positive interval", "key", key, "value", seconds, "default_seconds", defaultSeconds)
+		seconds = defaultSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	graphqlotel "github.com/graph-gophers/graphql-go/trace/otel"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// maxGraphQLBodyBytes bounds a POST /graphql body
+const maxGraphQLBodyBytes = 1 << 20
+
+// maxMovieIDs matches maxItems of the ids parameter in services/movie-service/openapi.yaml
+const maxMovieIDs = 100
+
+//go:embed schema.graphql
+var graphqlSchemaSource string
+
+// graphqlSchema serves /graphql; set by initGraphQL at startup
+var graphqlSchema *graphql.Schema
+
+// initGraphQL parses the schema and checks it against the resolvers
+func initGraphQL() error {
+	schema, err := graphql.ParseSchema(graphqlSchemaSource, &graphqlResolver{},
+		graphql.UseFieldResolvers(),
+		// 목록 항목마다 병렬로 해석되어야 batchLoader 가 한 번에 모을 수 있음
+		graphql.MaxParallelism(64),
+		// User.bookings → Booking.user → ... 순환 조회 제한
+		graphql.MaxDepth(8),
+		graphql.Tracer(&graphqlotel.Tracer{Tracer: tracer}),
+	)
+	if err != nil {
+		return err
+	}
+	graphqlSchema = schema
+	return nil
+}
+
+// graphqlRequest is a GraphQL-over-HTTP request; GET uses the same names as query parameters
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// graphqlHandler executes a query with per-request loaders; GraphQL errors are answered with
+// 200 in the errors array, only malformed HTTP requests get a problem+json
+func graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	var req graphqlRequest
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "variables must be a JSON object")
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBodyBytes)).Decode(&req); err != nil {
+			platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if req.Query == "" {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "query is required")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphqlLoadersKey{}, newGraphQLLoaders(r.Context()))
+	resp := graphqlSchema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// upstreamError is an error response of a service called by a resolver; its problem code
+// becomes extensions.code of the GraphQL error
+type upstreamError struct {
+	problem platform.Problem
+}
+
+func (e upstreamError) Error() string {
+	return e.problem.Detail
+}
+
+func (e upstreamError) Extensions() map[string]any {
+	return map[string]any{
+		"code":      e.problem.Code,
+		"status":    e.problem.Status,
+		"service":   e.problem.Service,
+		"cluster":   e.problem.Cluster,
+		"upstream":  e.problem.Upstream,
+		"requestId": e.problem.RequestID,
+	}
+}
+
+// fetchUpstream GETs path from the service and decodes the JSON body into out; found is false on 404.
+// The call is recorded like a proxied request so GraphQL fan-out shows up in the traffic stats and UI.
+func fetchUpstream(ctx context.Context, service, path string, out any) (found bool, err error) {
+	route, ok := gatewayConfig.route(service)
+	if !ok {
+		return false, fmt.Errorf("no route for %s", service)
+	}
+	ctx = context.WithValue(ctx, proxyStartKey{}, time.Now())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(route.Upstream, "/")+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(platform.RequestIDHeader, platform.RequestID(ctx))
+
+	resp, err := tracedHTTPClient.Do(req)
+	if err != nil {
+		problem := upstreamProblem(req, service, err)
+		recordTraffic(req, TrafficRecord{Service: service, Cluster: "unknown", StatusCode: problem.Status, Source: RecordSourceProxyError})
+		return false, upstreamError{problem}
+	}
+	defer resp.Body.Close()
+
+	cluster := resp.Header.Get("X-Service-Cluster")
+	if cluster == "" {
+		cluster = "unknown"
+	}
+	recordTraffic(req, TrafficRecord{
+		Service:    service,
+		Cluster:    cluster,
+		Pod:        resp.Header.Get("X-Pod-Name"),
+		StatusCode: resp.StatusCode,
+		Source:     RecordSourceResponse,
+	})
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		problem := platform.NewProblem(req, resp.StatusCode, upstreamCode(resp.StatusCode), "")
+		if isJSONContentType(resp.Header.Get("Content-Type")) {
+			json.NewDecoder(io.LimitReader(resp.Body, maxUpstreamDetailBytes)).Decode(&problem)
+		}
+		// upstreamProblem 과 같이 detail 앞에 서비스 이름
+		problem.Detail = service + ": " + cmp.Or(problem.Detail, http.StatusText(resp.StatusCode))
+		problem.Upstream = service
+		if problem.Cluster == "" {
+			problem.Cluster = cluster
+		}
+		return false, upstreamError{problem}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("%s: invalid response: %w", service, err)
+	}
+	return true, nil
+}
+
+// graphqlLoadersKey stores the loaders of a /graphql request in its context
+type graphqlLoadersKey struct{}
+
+// graphqlLoaders batch and deduplicate the upstream calls of one request
+type graphqlLoaders struct {
+	users        *batchLoader[*gqlUser]
+	movies       *batchLoader[*gqlMovie]
+	userBookings *batchLoader[[]*gqlBooking]
+}
+
+func newGraphQLLoaders(ctx context.Context) *graphqlLoaders {
+	return &graphqlLoaders{
+		// user-service 와 booking-service 는 일괄 조회 API 가 없어 key 별로 병렬 호출 (중복 제거만)
+		users: newBatchLoader(ctx, fetchEach(func(ctx context.Context, id string) (*gqlUser, bool, error) {
+			var user gqlUser
+			found, err := fetchUpstream(ctx, "user-service", "/users/"+url.PathEscape(id), &user)
+			return &user, found, err
+		})),
+		userBookings: newBatchLoader(ctx, fetchEach(func(ctx context.Context, id string) ([]*gqlBooking, bool, error) {
+			var bookings []*gqlBooking
+			found, err := fetchUpstream(ctx, "booking-service", "/bookings/user/"+url.PathEscape(id), &bookings)
+			return bookings, found, err
+		})),
+		movies: newBatchLoader(ctx, fetchMoviesByIDs),
+	}
+}
+
+// fetchMoviesByIDs loads the movies with GET /movies/?ids=..., one call per maxMovieIDs keys
+func fetchMoviesByIDs(ctx context.Context, ids []string) (map[string]*gqlMovie, error) {
+	movies := make(map[string]*gqlMovie, len(ids))
+	for start := 0; start < len(ids); start += maxMovieIDs {
+		chunk := ids[start:min(start+maxMovieIDs, len(ids))]
+		var found []*gqlMovie
+		if _, err := fetchUpstream(ctx, "movie-service", "/movies/?ids="+url.QueryEscape(strings.Join(chunk, ",")), &found); err != nil {
+			return nil, err
+		}
+		for _, movie := range found {
+			movies[string(movie.ID)] = movie
+		}
+	}
+	return movies, nil
+}
+
+func loadersFrom(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(graphqlLoadersKey{}).(*graphqlLoaders)
+}
+
+// graphqlResolver resolves the Query type
+type graphqlResolver struct{}
+
+func (*graphqlResolver) Users(ctx context.Context) ([]*gqlUser, error) {
+	return fetchList[gqlUser](ctx, "user-service", "/users/")
+}
+
+func (*graphqlResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*gqlUser, error) {
+	return loadOptional(ctx, loadersFrom(ctx).users, string(args.ID))
+}
+
+func (*graphqlResolver) Movies(ctx context.Context) ([]*gqlMovie, error) {
+	return fetchList[gqlMovie](ctx, "movie-service", "/movies/")
+}
+
+func (*graphqlResolver) Movie(ctx context.Context, args struct{ ID graphql.ID }) (*gqlMovie, error) {
+	return loadOptional(ctx, loadersFrom(ctx).movies, string(args.ID))
+}
+
+func (*graphqlResolver) Bookings(ctx context.Context) ([]*gqlBooking, error) {
+	return fetchList[gqlBooking](ctx, "booking-service", "/bookings/")
+}
+
+// fetchList resolves a non-null list field; an upstream null is an empty list
+func fetchList[T any](ctx context.Context, service, path string) ([]*T, error) {
+	var items []*T
+	if _, err := fetchUpstream(ctx, service, path, &items); err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []*T{}
+	}
+	return items, nil
+}
+
+// loadOptional resolves a nullable object field: unknown IDs are null, not errors
+func loadOptional[V any](ctx context.Context, loader *batchLoader[*V], id string) (*V, error) {
+	value, found, err := loader.load(ctx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+	return value, nil
+}
+
+// gqlUser, gqlMovie and gqlBooking decode the REST responses; scalar fields are resolved by name
+type gqlUser struct {
+	ID    graphql.ID `json:"id"`
+	Name  string     `json:"name"`
+	Email string     `json:"email"`
+}
+
+func (u *gqlUser) Bookings(ctx context.Context) ([]*gqlBooking, error) {
+	bookings, _, err := loadersFrom(ctx).userBookings.load(ctx, string(u.ID))
+	if bookings == nil {
+		bookings = []*gqlBooking{}
+	}
+	return bookings, err
+}
+
+type gqlMovie struct {
+	ID       graphql.ID `json:"id"`
+	Title    string     `json:"title"`
+	Director string     `json:"director"`
+	Genre    string     `json:"genre"`
+	Year     *int32     `json:"year"`
+}
+
+type gqlBooking struct {
+	ID      graphql.ID `json:"id"`
+	UserID  graphql.ID `json:"userId"`
+	MovieID graphql.ID `json:"movieId"`
+	SeatIDs []string   `json:"seats"`
+}
+
+// Seats keeps the list non-null for bookings stored without seats
+func (b *gqlBooking) Seats() []string {
+	if b.SeatIDs == nil {
+		return []string{}
+	}
+	return b.SeatIDs
+}
+
+func (b *gqlBooking) User(ctx context.Context) (*gqlUser, error) {
+	return loadOptional(ctx, loadersFrom(ctx).users, string(b.UserID))
+}
+
+func (b *gqlBooking) Movie(ctx context.Context) (*gqlMovie, error) {
+	return loadOptional(ctx, loadersFrom(ctx).movies, string(b.MovieID))
+}
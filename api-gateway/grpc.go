@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
+)
+
+// grpcErrorDomain is the ErrorInfo domain set by the services (pkg/platform GRPCError)
+const grpcErrorDomain = "theater-msa"
+
+// maxTranscodeBodyBytes bounds the REST body decoded into a request message
+const maxTranscodeBodyBytes = 1 << 20
+
+// grpcCall is one REST operation served by a unary RPC; invoke returns the REST-shaped JSON body
+type grpcCall struct {
+	method string // full method, e.g. /theater.v1.UserService/GetUser
+	status int    // REST 와 같은 성공 상태 코드
+	invoke func(ctx context.Context, conn *grpc.ClientConn, body []byte, opts ...grpc.CallOption) ([]byte, error)
+}
+
+// invalidBodyError reports a REST body that does not decode into the request message
+type invalidBodyError struct{ err error }
+
+func (e invalidBodyError) Error() string { return "Invalid request body: " + e.err.Error() }
+
+// grpcTranscoders map the REST operations of each service, relative to its path prefix, to RPCs;
+// anything else (unknown paths, 405) is proxied over HTTP as before
+var grpcTranscoders = map[string]func(method, path string, query url.Values) (grpcCall, bool){
+	"user-service":    userCall,
+	"movie-service":   movieCall,
+	"booking-service": bookingCall,
+}
+
+// grpcClients holds one connection per upstream; all calls are multiplexed over its HTTP/2 connection
+var grpcClients = struct {
+	sync.Mutex
+	conns map[string]*grpc.ClientConn
+}{conns: map[string]*grpc.ClientConn{}}
+
+// grpcConn returns the connection to target; it is dialed lazily on the first call
+func grpcConn(target string) (*grpc.ClientConn, error) {
+	grpcClients.Lock()
+	defer grpcClients.Unlock()
+	if conn, ok := grpcClients.conns[target]; ok {
+		return conn, nil
+	}
+	// 사이드카가 있으면 Envoy 가 이 연결 위의 요청을 하나씩 가중치대로 분산 (HTTP/1.1 은 연결 단위)
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	grpcClients.conns[target] = conn
+	return conn, nil
+}
+
+// closeGRPCClients closes the upstream connections at shutdown
+func closeGRPCClients(context.Context) error {
+	grpcClients.Lock()
+	defer grpcClients.Unlock()
+	var errs []error
+	for target, conn := range grpcClients.conns {
+		errs = append(errs, conn.Close())
+		delete(grpcClients.conns, target)
+	}
+	return errors.Join(errs...)
+}
+
+// grpcCallFor returns the RPC serving the request when transcoding is enabled for the route
+func grpcCallFor(route RouteConfig, r *http.Request) (grpcCall, bool) {
+	if !gatewayConfig.GRPCTranscoding || route.GRPCUpstream == "" {
+		return grpcCall{}, false
+	}
+	transcoder, ok := grpcTranscoders[route.Service]
+	if !ok {
+		return grpcCall{}, false
+	}
+	return transcoder(r.Method, strings.TrimPrefix(r.URL.Path, route.PathPrefix), r.URL.Query())
+}
+
+func userCall(method, path string, _ url.Values) (grpcCall, bool) {
+	switch {
+	case method == http.MethodGet && path == "":
+		return grpcCall{theaterpb.UserService_ListUsers_FullMethodName, http.StatusOK,
+			func(ctx context.Context, conn *grpc.ClientConn, _ []byte, opts ...grpc.CallOption) ([]byte, error) {
+				resp, err := theaterpb.NewUserServiceClient(conn).ListUsers(ctx, &theaterpb.ListUsersRequest{}, opts...)
+				if err != nil {
+					return nil, err
+				}
+				return marshalList(resp.GetUsers())
+			}}, true
+	case method == http.MethodPost && path == "":
+		return grpcCall{theaterpb.UserService_CreateUser_FullMethodName, http.StatusCreated,
+			func(ctx context.Context, conn *grpc.ClientConn, body []byte, opts ...grpc.CallOption) ([]byte, error) {
+				req := &theaterpb.CreateUserRequest{}
+				if err := unmarshalBody(body, req); err != nil {
+					return nil, err
+				}
+				return marshalMessage(theaterpb.NewUserServiceClient(conn).CreateUser(ctx, req, opts...))
+			}}, true
+	case method == http.MethodGet && path != "" && !strings.Contains(path, "/"):
+		return grpcCall{theaterpb.UserService_GetUser_FullMethodName, http.StatusOK,
+			func(ctx context.Context, conn *grpc.ClientConn, _ []byte, opts ...grpc.CallOption) ([]byte, error) {
+				return marshalMessage(theaterpb.NewUserServiceClient(conn).GetUser(ctx, &theaterpb.GetUserRequest{Id: path}, opts...))
+			}}, true
+	}
+	return grpcCall{}, false
+}
+
+func movieCall(method, path string, query url.Values) (grpcCall, bool) {
+	switch {
+	case method == http.MethodGet && path == "":
+		req := &theaterpb.ListMoviesRequest{}
+		if ids := query.Get("ids"); ids != "" {
+			req.Ids = strings.Split(ids, ",")
+		}
+		return grpcCall{theaterpb.MovieService_ListMovies_FullMethodName, http.StatusOK,
+			func(ctx context.Context, conn *grpc.ClientConn, _ []byte, opts ...grpc.CallOption) ([]byte, error) {
+				resp, err := theaterpb.NewMovieServiceClient(conn).ListMovies(ctx, req, opts...)
+				if err != nil {
+					return nil, err
+				}
+				return marshalList(resp.GetMovies())
+			}}, true
+	case method == http.MethodPost && path == "":
+		return grpcCall{theaterpb.MovieService_CreateMovie_FullMethodName, http.StatusCreated,
+			func(ctx context.Context, conn *grpc.ClientConn, body []byte, opts ...grpc.CallOption) ([]byte, error) {
+				req := &theaterpb.CreateMovieRequest{}
+				if err := unmarshalBody(body, req); err != nil {
+					return nil, err
+				}
+				return marshalMessage(theaterpb.NewMovieServiceClient(conn).CreateMovie(ctx, req, opts...))
+			}}, true
+	case method == http.MethodGet && path != "" && !strings.Contains(path, "/"):
+		return grpcCall{theaterpb.MovieService_GetMovie_FullMethodName, http.StatusOK,
+			func(ctx context.Context, conn *grpc.ClientConn, _ []byte, opts ...grpc.CallOption) ([]byte, error) {
+				return marshalMessage(theaterpb.NewMovieServiceClient(conn).GetMovie(ctx, &theaterpb.GetMovieRequest{Id: path}, opts...))
+			}}, true
+	}
+	return grpcCall{}, false
+}
+
+func bookingCall(method, path string, _ url.Values) (grpcCall, bool) {
+	userID, isUserPath := strings.CutPrefix(path, "user/")
+	switch {
+	case method == http.MethodGet && path == "":
+		return grpcCall{theaterpb.BookingService_ListBookings_FullMethodName, http.StatusOK,
+			func(ctx context.Context, conn *grpc.ClientConn, _ []byte, opts ...grpc.CallOption) ([]byte, error) {
+				resp, err := theaterpb.NewBookingServiceClient(conn).ListBookings(ctx, &theaterpb.ListBookingsRequest{}, opts...)
+				if err != nil {
+					return nil, err
+				}
+				return marshalList(resp.GetBookings())
+			}}, true
+	case method == http.MethodPost && path == "":
+		return grpcCall{theaterpb.BookingService_CreateBooking_FullMethodName, http.StatusCreated,
+			func(ctx context.Context, conn *grpc.ClientConn, body []byte, opts ...grpc.CallOption) ([]byte, error) {
+				req := &theaterpb.CreateBookingRequest{}
+				if err := unmarshalBody(body, req); err != nil {
+					return nil, err
+				}
+				return marshalMessage(theaterpb.NewBookingServiceClient(conn).CreateBooking(ctx, req, opts...))
+			}}, true
+	case method == http.MethodGet && isUserPath && userID != "":
+		return grpcCall{theaterpb.BookingService_ListUserBookings_FullMethodName, http.StatusOK,
+			func(ctx context.Context, conn *grpc.ClientConn, _ []byte, opts ...grpc.CallOption) ([]byte, error) {
+				resp, err := theaterpb.NewBookingServiceClient(conn).ListUserBookings(ctx, &theaterpb.ListUserBookingsRequest{UserId: userID}, opts...)
+				if err != nil {
+					return nil, err
+				}
+				return marshalList(resp.GetBookings())
+			}}, true
+	}
+	return grpcCall{}, false
+}
+
+// unmarshalBody decodes the REST body like the handlers do: unknown fields are ignored
+func unmarshalBody(body []byte, req proto.Message) error {
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+		return invalidBodyError{err}
+	}
+	return nil
+}
+
+// responseJSON emits unset fields too, so the bodies have the same members as the REST responses
+var responseJSON = protojson.MarshalOptions{EmitUnpopulated: true}
+
+func marshalMessage(msg proto.Message, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return responseJSON.Marshal(msg)
+}
+
+// marshalList renders a list response as the plain JSON array answered by the REST handlers
+func marshalList[M proto.Message](items []M) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encoded, err := responseJSON.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encoded)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// metadataCarrier adapts gRPC metadata to the OpenTelemetry propagator, like pkg/platform
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// transcodeAndRecord serves the request with call on the route's gRPC upstream and records the
+// serving cluster from the response header metadata, like the ModifyResponse of newReverseProxy
+func transcodeAndRecord(route RouteConfig, call grpcCall, w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), proxyStartKey{}, time.Now())
+	r = r.WithContext(ctx)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTranscodeBodyBytes))
+	if err != nil {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+	conn, err := grpcConn(route.GRPCUpstream)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid gRPC upstream", "target_service", route.Service, "grpc_upstream", route.GRPCUpstream, "error", err)
+		platform.WriteProblem(w, upstreamProblem(r, route.Service, err))
+		return
+	}
+
+	grpcService, grpcMethod, _ := strings.Cut(strings.TrimPrefix(call.method, "/"), "/")
+	ctx, span := tracer.Start(ctx, call.method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", grpcService),
+			attribute.String("rpc.method", grpcMethod),
+		),
+	)
+	defer span.End()
+
+	// REST 프록시와 같이 traceparent 와 X-Request-Id 를 서비스로 전달
+	md := metadata.Pairs(strings.ToLower(platform.RequestIDHeader), r.Header.Get(platform.RequestIDHeader))
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	var header metadata.MD
+	out, err := call.invoke(metadata.NewOutgoingContext(ctx, md), conn, body, grpc.Header(&header))
+
+	cluster := metadataCarrier(header).Get("x-service-cluster")
+	if cluster == "" {
+		cluster = "unknown"
+	}
+	serviceName := metadataCarrier(header).Get("x-service-name")
+	if serviceName == "" {
+		serviceName = route.Service
+	}
+	pod := metadataCarrier(header).Get("x-pod-name")
+	code := status.Code(err)
+	span.SetAttributes(
+		attribute.String("theater.service", serviceName),
+		attribute.String("theater.cluster", cluster),
+		attribute.String("theater.pod", pod),
+		attribute.Int("rpc.grpc.status_code", int(code)),
+	)
+
+	if err != nil {
+		var invalid invalidBodyError
+		if errors.As(err, &invalid) {
+			platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, invalid.Error())
+			return
+		}
+		problem := grpcProblem(r, route.Service, err)
+		source := RecordSourceResponse
+		if header == nil {
+			// 파드가 응답하지 않음 (연결 실패, Envoy 가 반환한 UNAVAILABLE 등)
+			slog.WarnContext(ctx, "gRPC upstream error", "target_service", route.Service, "grpc_method", call.method, "error", err)
+			source = RecordSourceProxyError
+		} else {
+			problem.Cluster = cluster
+			problem.Pod = pod
+		}
+		if problem.Status >= http.StatusInternalServerError {
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+		recordTraffic(r, TrafficRecord{Service: serviceName, Cluster: cluster, Pod: pod, StatusCode: problem.Status, Source: source})
+		platform.WriteProblem(w, problem)
+		return
+	}
+
+	recordTraffic(r, TrafficRecord{Service: serviceName, Cluster: cluster, Pod: pod, StatusCode: call.status, Source: RecordSourceResponse})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Service-Cluster", cluster)
+	w.Header().Set("X-Service-Name", serviceName)
+	w.Header().Set("X-Pod-Name", pod)
+	w.Header().Set("X-Upstream-Protocol", "grpc")
+	w.WriteHeader(call.status)
+	w.Write(out)
+}
+
+// httpStatusForCode maps gRPC status codes to the statuses the REST handlers answer
+func httpStatusForCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// grpcProblem converts a gRPC status to problem+json; the ErrorInfo reason set by the service
+// is the same code its REST handler answers, otherwise the gateway answers for the upstream
+func grpcProblem(r *http.Request, service string, err error) platform.Problem {
+	st := status.Convert(err)
+	httpStatus := httpStatusForCode(st.Code())
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != grpcErrorDomain {
+			continue
+		}
+		problem := platform.NewProblem(r, httpStatus, info.GetReason(), st.Message())
+		problem.Service = info.GetMetadata()["service"]
+		problem.Cluster = info.GetMetadata()["cluster"]
+		problem.Pod = info.GetMetadata()["pod"]
+		if requestID := info.GetMetadata()["requestId"]; requestID != "" {
+			problem.RequestID = requestID
+		}
+		return problem
+	}
+
+	code := codeUpstreamError
+	switch st.Code() {
+	case codes.Unavailable:
+		code = codeUpstreamUnavailable
+	case codes.DeadlineExceeded:
+		code = codeUpstreamTimeout
+	}
+	problem := platform.NewProblem(r, httpStatus, code, service+": "+st.Message())
+	problem.Upstream = service
+	return problem
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// upstreamCheckTimeout bounds each upstream /healthz call made by the readiness probe
+const upstreamCheckTimeout = 2 * time.Second
+
+// started is set once an upstream answered for the first time; draining is set on SIGTERM (platform.Serve drain hook)
+var (
+	started  atomic.Bool
+	draining atomic.Bool
+)
+
+// probeHTTPClient is untraced so probe traffic does not show up in the service traces
+var probeHTTPClient = &http.Client{Timeout: upstreamCheckTimeout}
+
+// checkUpstream calls the liveness endpoint of a routed service through the mesh
+func checkUpstream(ctx context.Context, route RouteConfig) BackendHealth {
+	health := BackendHealth{Name: route.Service, Kind: "upstream"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(route.Upstream, "/")+"/healthz", nil)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	start := time.Now()
+	resp, err := probeHTTPClient.Do(req)
+	health.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		health.Error = resp.Status
+		return health
+	}
+	health.Connected = true
+	return health
+}
+
+// checkUpstreams probes every route concurrently
+func checkUpstreams(ctx context.Context) []BackendHealth {
+	results := make([]BackendHealth, len(gatewayConfig.Routes))
+	var wg sync.WaitGroup
+	for i, route := range gatewayConfig.Routes {
+		wg.Add(1)
+		go func(i int, route RouteConfig) {
+			defer wg.Done()
+			results[i] = checkUpstream(ctx, route)
+		}(i, route)
+	}
+	wg.Wait()
+	return results
+}
+
+// anyConnected reports whether at least one backend answered
+func anyConnected(backends []BackendHealth) bool {
+	for _, backend := range backends {
+		if backend.Connected {
+			return true
+		}
+	}
+	return false
+}
+
+// readinessReport adds the upstream checks to the Kubernetes/Istio report.
+// A single failing upstream or API server only degrades the gateway: the UI and the other routes keep working.
+func readinessReport(ctx context.Context) (HealthReport, bool) {
+	var (
+		report    HealthReport
+		upstreams []BackendHealth
+		wg        sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		report = checkBackends(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		upstreams = checkUpstreams(ctx)
+	}()
+	wg.Wait()
+
+	report.Backends = append(report.Backends, upstreams...)
+	if !anyConnected(upstreams) {
+		report.Status = "unavailable"
+		return report, false
+	}
+	if report.Status == "ok" {
+		for _, upstream := range upstreams {
+			if !upstream.Connected {
+				report.Status = "degraded"
+			}
+		}
+	}
+	return report, true
+}
+
+func writeProbe(w http.ResponseWriter, report HealthReport, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// newHealthReport describes the Kubernetes connection without calling any backend
+func newHealthReport(status string) HealthReport {
+	return HealthReport{
+		Status:       status,
+		Mode:         kubeConn.Mode,
+		Kubeconfig:   kubeConn.Kubeconfig,
+		Contexts:     kubeConn.Contexts,
+		Server:       kubeConn.Server,
+		LocalCluster: localClusterName(),
+		Error:        kubeConn.Error,
+	}
+}
+
+// healthzHandler is the liveness probe; it answers 200 while the process serves and calls no backend
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, newHealthReport("ok"), true)
+}
+
+// readyzHandler fails while draining or when no upstream answers; backend details are always included
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if draining.Load() {
+		writeProbe(w, newHealthReport("draining"), false)
+		return
+	}
+	report, ok := readinessReport(r.Context())
+	if ok {
+		started.Store(true)
+	}
+	writeProbe(w, report, ok)
+}
+
+// startupzHandler succeeds once any upstream has answered; until then liveness and readiness are not probed
+func startupzHandler(w http.ResponseWriter, r *http.Request) {
+	if started.Load() {
+		writeProbe(w, newHealthReport("ok"), true)
+		return
+	}
+	report := newHealthReport("starting")
+	report.Backends = checkUpstreams(r.Context())
+	ok := anyConnected(report.Backends)
+	if ok {
+		started.Store(true)
+		report.Status = "ok"
+	}
+	writeProbe(w, report, ok)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	istioclient "istio.io/client-go/pkg/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Kubernetes connection modes reported by /healthz and /readyz
+const (
+	ModeInCluster  = "in-cluster"
+	ModeKubeconfig = "kubeconfig"
+	ModeNone       = "none"
+)
+
+// stringList is a repeatable command line flag
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// kubeConnection records how the Kubernetes clients were created
+type kubeConnection struct {
+	Mode       string
+	Kubeconfig string
+	Contexts   []string
+	Server     string
+	Error      string
+}
+
+var kubeConn = kubeConnection{Mode: ModeNone}
+
+// localRESTConfig prefers the in-cluster config and falls back to kubeconfig (--kubeconfig, KUBECONFIG, ~/.kube/config)
+func localRESTConfig(kubeconfig, kubeContext string) (*rest.Config, string, error) {
+	if kubeconfig == "" && kubeContext == "" {
+		if config, err := rest.InClusterConfig(); err == nil {
+			return config, ModeInCluster, nil
+		}
+	}
+	config, err := kubeconfigRESTConfig(kubeconfig, kubeContext)
+	return config, ModeKubeconfig, err
+}
+
+func kubeconfigRESTConfig(kubeconfig, kubeContext string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+}
+
+// initKubernetesClients creates the local Kubernetes and Istio clients; the first context is the
+// local cluster and every further context is registered as a remote cluster under its context name
+func initKubernetesClients(kubeconfig string, contexts []string) {
+	localContext := ""
+	if len(contexts) > 0 {
+		localContext = contexts[0]
+		defaultLocalCluster = contexts[0]
+	}
+	kubeConn.Kubeconfig = kubeconfig
+	kubeConn.Contexts = contexts
+
+	config, mode, err := localRESTConfig(kubeconfig, localContext)
+	if err != nil {
+		kubeConn.Error = err.Error()
+		slog.Warn("Kubernetes features disabled: no in-cluster config and no usable kubeconfig", "error", err)
+		return
+	}
+
+	// 인터페이스 전역에 nil 포인터가 들어가지 않도록 성공한 경우에만 대입
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		kubeConn.Error = err.Error()
+		slog.Error("Failed to create Kubernetes client", "error", err)
+		return
+	}
+	kubernetesClient = kubeClient
+	kubeConn.Mode = mode
+	kubeConn.Server = config.Host
+
+	// Istio 클라이언트 초기화
+	if meshClient, err := istioclient.NewForConfig(config); err != nil {
+		slog.Error("Failed to create Istio client", "error", err)
+	} else {
+		istioClient = meshClient
+	}
+	slog.Info("Connected to Kubernetes", "server", config.Host, "mode", mode)
+
+	if len(contexts) < 2 {
+		return
+	}
+	for _, remoteContext := range contexts[1:] {
+		remoteConfig, err := kubeconfigRESTConfig(kubeconfig, remoteContext)
+		if err != nil {
+			slog.Warn("Skipping kubeconfig context", "context", remoteContext, "error", err)
+			continue
+		}
+		remoteConfig.Timeout = 5 * time.Second
+		client, err := kubernetes.NewForConfig(remoteConfig)
+		if err != nil {
+			slog.Warn("Skipping kubeconfig context", "context", remoteContext, "error", err)
+			continue
+		}
+		clusters.addStatic(memberCluster{Name: remoteContext, Client: client})
+		slog.Info("Registered remote cluster from kubeconfig context", "remote_cluster", remoteContext, "server", remoteConfig.Host)
+	}
+}
+
+// BackendHealth is the connectivity of one backend
+type BackendHealth struct {
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"` // kubernetes, istio, upstream
+	Connected bool    `json:"connected"`
+	Version   string  `json:"version,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport is the body of /healthz, /readyz and /startupz
+type HealthReport struct {
+	Status       string          `json:"status"` // ok, degraded, standalone, starting, draining, unavailable
+	Mode         string          `json:"mode"`
+	Kubeconfig   string          `json:"kubeconfig,omitempty"`
+	Contexts     []string        `json:"contexts,omitempty"`
+	Server       string          `json:"server,omitempty"`
+	LocalCluster string          `json:"localCluster"`
+	Error        string          `json:"error,omitempty"`
+	Backends     []BackendHealth `json:"backends,omitempty"`
+}
+
+// checkKubernetes calls /version on the cluster's API server
+func checkKubernetes(ctx context.Context, member memberCluster) BackendHealth {
+	health := BackendHealth{Name: member.Name, Kind: "kubernetes"}
+	start := time.Now()
+	body, err := member.Client.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	health.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	var version struct {
+		GitVersion string `json:"gitVersion"`
+	}
+	json.Unmarshal(body, &version)
+	health.Connected = true
+	health.Version = version.GitVersion
+	return health
+}
+
+// checkIstio lists one VirtualService to verify the Istio API is reachable
+func checkIstio(ctx context.Context) BackendHealth {
+	health := BackendHealth{Name: localClusterName(), Kind: "istio"}
+	if istioClient == nil {
+		health.Error = errIstioUnavailable.Error()
+		return health
+	}
+	start := time.Now()
+	_, err := istioClient.NetworkingV1().VirtualServices(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{Limit: 1})
+	health.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.Connected = true
+	return health
+}
+
+// checkBackends probes every configured backend concurrently
+func checkBackends(ctx context.Context) HealthReport {
+	// Kubernetes 연결 없이 실행 중이면 프록시 기능만 동작
+	report := newHealthReport("standalone")
+	if kubernetesClient == nil {
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	members, err := clusters.members(ctx)
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	results := make([]BackendHealth, len(members)+1)
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, member memberCluster) {
+			defer wg.Done()
+			results[i] = checkKubernetes(ctx, member)
+		}(i, member)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[len(members)] = checkIstio(ctx)
+	}()
+	wg.Wait()
+
+	report.Status = "ok"
+	for _, result := range results {
+		if !result.Connected {
+			report.Status = "degraded"
+		}
+	}
+	report.Backends = results
+	return report
+}
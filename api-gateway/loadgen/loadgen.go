@@ -0,0 +1,481 @@
+// Package loadgen fires a weighted mix of requests at the gateway and reports
+// which cluster served each service, the status codes and the latency distribution.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NoCluster is reported when a response carries no X-Service-Cluster header
+const NoCluster = "none"
+
+// Target is one endpoint of the request mix
+type Target struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Weight int    `json:"weight"`
+}
+
+// DefaultTargets is the even mix of the three services
+var DefaultTargets = []Target{
+	{Name: "user-service", Path: "/users/", Weight: 1},
+	{Name: "movie-service", Path: "/movies/", Weight: 1},
+	{Name: "booking-service", Path: "/bookings/", Weight: 1},
+}
+
+// Config controls a load run; the run stops at Duration or after Requests, whichever comes first
+type Config struct {
+	BaseURL     string
+	Targets     []Target
+	Rate        float64 // 초당 요청 수, 0이면 동시성 한도 내에서 최대 속도
+	Concurrency int
+	Duration    time.Duration
+	Requests    int
+	Timeout     time.Duration
+	Headers     http.Header
+}
+
+// Bucket counts the requests whose latency is at most UpperMs
+type Bucket struct {
+	UpperMs float64 `json:"upperMs"` // 마지막 버킷은 +Inf 대신 -1
+	Count   int     `json:"count"`
+}
+
+// bucketBounds are the histogram upper bounds in milliseconds
+var bucketBounds = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// LatencySummary describes the latency distribution in milliseconds
+type LatencySummary struct {
+	MinMs  float64 `json:"minMs"`
+	MeanMs float64 `json:"meanMs"`
+	P50Ms  float64 `json:"p50Ms"`
+	P90Ms  float64 `json:"p90Ms"`
+	P95Ms  float64 `json:"p95Ms"`
+	P99Ms  float64 `json:"p99Ms"`
+	MaxMs  float64 `json:"maxMs"`
+}
+
+// TargetReport aggregates the results of one target
+type TargetReport struct {
+	Name           string             `json:"name"`
+	Path           string             `json:"path"`
+	Requests       int                `json:"requests"`
+	Failures       int                `json:"failures"` // 응답을 받지 못한 요청
+	Clusters       map[string]int     `json:"clusters"`
+	ClusterPercent map[string]float64 `json:"clusterPercent"`
+	StatusCodes    map[string]int     `json:"statusCodes"`
+	Latency        LatencySummary     `json:"latency"`
+	Histogram      []Bucket           `json:"histogram"`
+	Errors         map[string]int     `json:"errors,omitempty"`
+}
+
+// Report is the result of a load run
+type Report struct {
+	BaseURL         string         `json:"baseUrl"`
+	StartedAt       string         `json:"startedAt"`
+	DurationSeconds float64        `json:"durationSeconds"`
+	Rate            float64        `json:"rate"`
+	Concurrency     int            `json:"concurrency"`
+	Requests        int            `json:"requests"`
+	Failures        int            `json:"failures"`
+	AchievedRate    float64        `json:"achievedRate"`
+	SkippedTicks    int            `json:"skippedTicks"` // 동시성 한도에 막혀 보내지 못한 요청 틱, 0 보다 크면 rate 미달
+	Targets         []TargetReport `json:"targets"`
+}
+
+// result is the outcome of a single request
+type result struct {
+	target  int
+	cluster string
+	status  int
+	latency time.Duration
+	err     error
+	aborted bool // 실행이 끝나(또는 중단되어) 취소된 요청
+}
+
+// validate fills defaults and rejects unusable configurations
+func (c *Config) validate() error {
+	if c.BaseURL == "" {
+		return errors.New("base URL is required")
+	}
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	if len(c.Targets) == 0 {
+		c.Targets = DefaultTargets
+	}
+	for _, target := range c.Targets {
+		if target.Weight <= 0 {
+			return fmt.Errorf("weight of %s must be positive", target.Name)
+		}
+	}
+	if c.Rate < 0 {
+		return errors.New("rate must not be negative")
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = 10
+	}
+	if c.Duration <= 0 && c.Requests <= 0 {
+		return errors.New("duration or number of requests is required")
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return nil
+}
+
+// ParseMix parses "users=2,movies=1,bookings=1" into targets; names may omit "-service"
+func ParseMix(mix string) ([]Target, error) {
+	known := map[string]Target{}
+	for _, target := range DefaultTargets {
+		known[target.Name] = target
+		known[strings.TrimSuffix(target.Name, "-service")] = target
+		known[strings.Trim(target.Path, "/")] = target
+	}
+
+	var targets []Target
+	for _, item := range strings.Split(mix, ",") {
+		name, weightText, found := strings.Cut(strings.TrimSpace(item), "=")
+		weight := 1
+		if found {
+			var err error
+			if weight, err = strconv.Atoi(weightText); err != nil || weight < 0 {
+				return nil, fmt.Errorf("invalid weight in %q", item)
+			}
+		}
+		target, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown target %q", name)
+		}
+		if weight == 0 {
+			continue
+		}
+		target.Weight = weight
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("mix selects no targets")
+	}
+	return targets, nil
+}
+
+// scheduler picks targets with smooth weighted round-robin so the mix is identical on every run
+type scheduler struct {
+	targets []Target
+	current []int
+	total   int
+}
+
+func newScheduler(targets []Target) *scheduler {
+	s := &scheduler{targets: targets, current: make([]int, len(targets))}
+	for _, target := range targets {
+		s.total += target.Weight
+	}
+	return s
+}
+
+func (s *scheduler) next() int {
+	best := 0
+	for i, target := range s.targets {
+		s.current[i] += target.Weight
+		if s.current[i] > s.current[best] {
+			best = i
+		}
+	}
+	s.current[best] -= s.total
+	return best
+}
+
+// Run executes the load described by cfg and returns the report
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	jobs := make(chan int)
+	results := make(chan result, cfg.Concurrency)
+
+	var workers sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for target := range jobs {
+				results <- send(ctx, client, cfg, target)
+			}
+		}()
+	}
+
+	started := time.Now()
+	var skipped int // close(results) 이후에만 읽음
+	go func() {
+		skipped = produce(ctx, cfg, jobs)
+		close(jobs)
+		workers.Wait()
+		close(results)
+	}()
+
+	collected := make([]result, 0, 1024)
+	for res := range results {
+		collected = append(collected, res)
+	}
+	report := buildReport(cfg, started, time.Since(started), collected)
+	report.SkippedTicks = skipped
+	return report, nil
+}
+
+// produce emits target indexes at the configured rate until the run ends and returns the ticks it missed
+func produce(ctx context.Context, cfg Config, jobs chan<- int) (skipped int) {
+	sched := newScheduler(cfg.Targets)
+
+	var tick <-chan time.Time
+	var interval time.Duration
+	if cfg.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / cfg.Rate)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	last := time.Now()
+	for sent := 0; cfg.Requests <= 0 || sent < cfg.Requests; sent++ {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return skipped
+			case now := <-tick:
+				// time.Ticker 는 받는 쪽이 늦으면 틱을 버림: 받은 틱 사이의 간격으로 놓친 틱을 계산
+				skipped += missedTicks(now.Sub(last), interval)
+				last = now
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return skipped
+		case jobs <- sched.next():
+		}
+	}
+	return skipped
+}
+
+// missedTicks is the number of ticks dropped between two received ticks elapsed apart
+func missedTicks(elapsed, interval time.Duration) int {
+	missed := int((elapsed+interval/2)/interval) - 1
+	if missed < 0 {
+		return 0
+	}
+	return missed
+}
+
+// send issues one request and records the serving cluster
+func send(ctx context.Context, client *http.Client, cfg Config, target int) result {
+	res := result{target: target, cluster: NoCluster}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.BaseURL+cfg.Targets[target].Path, nil)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	for name, values := range cfg.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		res.latency = time.Since(start)
+		res.err = err
+		// 오류 종류가 아니라 실행 컨텍스트로 판단: http.Client.Timeout 도 context.DeadlineExceeded 를 감쌈
+		res.aborted = ctx.Err() != nil
+		return res
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	res.latency = time.Since(start)
+
+	res.status = resp.StatusCode
+	if cluster := resp.Header.Get("X-Service-Cluster"); cluster != "" {
+		res.cluster = cluster
+	}
+	return res
+}
+
+func buildReport(cfg Config, started time.Time, elapsed time.Duration, results []result) *Report {
+	report := &Report{
+		BaseURL:         cfg.BaseURL,
+		StartedAt:       started.Format(time.RFC3339),
+		DurationSeconds: math.Round(elapsed.Seconds()*100) / 100,
+		Rate:            cfg.Rate,
+		Concurrency:     cfg.Concurrency,
+	}
+
+	latencies := make([][]float64, len(cfg.Targets))
+	report.Targets = make([]TargetReport, len(cfg.Targets))
+	for i, target := range cfg.Targets {
+		report.Targets[i] = TargetReport{
+			Name:           target.Name,
+			Path:           target.Path,
+			Clusters:       map[string]int{},
+			ClusterPercent: map[string]float64{},
+			StatusCodes:    map[string]int{},
+		}
+	}
+
+	for _, res := range results {
+		// 실행 시간이 끝나 취소된 요청은 집계하지 않음, 요청 타임아웃은 실패로 집계
+		if res.aborted {
+			continue
+		}
+		tr := &report.Targets[res.target]
+		tr.Requests++
+		report.Requests++
+		if res.err != nil {
+			tr.Failures++
+			report.Failures++
+			if tr.Errors == nil {
+				tr.Errors = map[string]int{}
+			}
+			tr.Errors[errorKind(res.err)]++
+			continue
+		}
+		tr.Clusters[res.cluster]++
+		tr.StatusCodes[strconv.Itoa(res.status)]++
+		latencies[res.target] = append(latencies[res.target], float64(res.latency.Microseconds())/1000)
+	}
+
+	if elapsed > 0 {
+		report.AchievedRate = math.Round(float64(report.Requests)/elapsed.Seconds()*10) / 10
+	}
+
+	for i := range report.Targets {
+		tr := &report.Targets[i]
+		answered := tr.Requests - tr.Failures
+		for cluster, count := range tr.Clusters {
+			tr.ClusterPercent[cluster] = math.Round(float64(count)/float64(answered)*1000) / 10
+		}
+		tr.Latency, tr.Histogram = summarize(latencies[i])
+	}
+	return report
+}
+
+func errorKind(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case strings.Contains(err.Error(), "connection refused"):
+		return "connection-refused"
+	}
+	return "other"
+}
+
+// summarize computes latency percentiles (nearest rank) and the histogram
+func summarize(values []float64) (LatencySummary, []Bucket) {
+	buckets := make([]Bucket, len(bucketBounds)+1)
+	for i, bound := range bucketBounds {
+		buckets[i].UpperMs = bound
+	}
+	buckets[len(bucketBounds)].UpperMs = -1
+
+	if len(values) == 0 {
+		return LatencySummary{}, buckets
+	}
+	sort.Float64s(values)
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+		index := sort.SearchFloat64s(bucketBounds, value)
+		buckets[index].Count++
+	}
+
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return LatencySummary{
+		MinMs:  round(values[0]),
+		MeanMs: round(sum / float64(len(values))),
+		P50Ms:  round(Percentile(values, 50)),
+		P90Ms:  round(Percentile(values, 90)),
+		P95Ms:  round(Percentile(values, 95)),
+		P99Ms:  round(Percentile(values, 99)),
+		MaxMs:  round(values[len(values)-1]),
+	}, buckets
+}
+
+// Percentile returns the nearest-rank percentile of sorted values (0 when empty);
+// the gateway's traffic statistics, rollouts and chaos hypotheses use it too
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// WriteText renders the report as a human-readable table
+func (r *Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "Target: %s  started %s  duration %.1fs\n", r.BaseURL, r.StartedAt, r.DurationSeconds)
+	fmt.Fprintf(w, "Requests: %d  failures: %d  achieved rate: %.1f req/s (concurrency %d)\n", r.Requests, r.Failures, r.AchievedRate, r.Concurrency)
+	if r.SkippedTicks > 0 {
+		fmt.Fprintf(w, "Skipped ticks: %d  (rate %.1f req/s not reached: all %d workers were busy, raise -concurrency)\n", r.SkippedTicks, r.Rate, r.Concurrency)
+	}
+
+	for _, tr := range r.Targets {
+		fmt.Fprintf(w, "\n== %s (%s)  requests %d  failures %d\n", tr.Name, tr.Path, tr.Requests, tr.Failures)
+
+		fmt.Fprintf(w, "  clusters:")
+		for _, cluster := range sortedKeys(tr.Clusters) {
+			fmt.Fprintf(w, "  %s %d (%.1f%%)", cluster, tr.Clusters[cluster], tr.ClusterPercent[cluster])
+		}
+		fmt.Fprintf(w, "\n  status:  ")
+		for _, code := range sortedKeys(tr.StatusCodes) {
+			fmt.Fprintf(w, "  %s x%d", code, tr.StatusCodes[code])
+		}
+		for _, kind := range sortedKeys(tr.Errors) {
+			fmt.Fprintf(w, "  %s x%d", kind, tr.Errors[kind])
+		}
+		fmt.Fprintf(w, "\n  latency:   min %.1fms  mean %.1fms  p50 %.1fms  p90 %.1fms  p95 %.1fms  p99 %.1fms  max %.1fms\n",
+			tr.Latency.MinMs, tr.Latency.MeanMs, tr.Latency.P50Ms, tr.Latency.P90Ms, tr.Latency.P95Ms, tr.Latency.P99Ms, tr.Latency.MaxMs)
+
+		answered := tr.Requests - tr.Failures
+		for _, bucket := range tr.Histogram {
+			if bucket.Count == 0 {
+				continue
+			}
+			label := fmt.Sprintf("<= %gms", bucket.UpperMs)
+			if bucket.UpperMs < 0 {
+				label = fmt.Sprintf("> %gms", bucketBounds[len(bucketBounds)-1])
+			}
+			bar := strings.Repeat("#", int(math.Ceil(float64(bucket.Count)/float64(answered)*40)))
+			fmt.Fprintf(w, "  %10s %6d %s\n", label, bucket.Count, bar)
+		}
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
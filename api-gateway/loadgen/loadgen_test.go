@@ -0,0 +1,202 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMix(t *testing.T) {
+	tests := []struct {
+		mix     string
+		want    []Target
+		wantErr bool
+	}{
+		{mix: "users=2,movies=1,bookings=1", want: []Target{
+			{Name: "user-service", Path: "/users/", Weight: 2},
+			{Name: "movie-service", Path: "/movies/", Weight: 1},
+			{Name: "booking-service", Path: "/bookings/", Weight: 1},
+		}},
+		{mix: "movie-service=3, user", want: []Target{
+			{Name: "movie-service", Path: "/movies/", Weight: 3},
+			{Name: "user-service", Path: "/users/", Weight: 1},
+		}},
+		{mix: "movie=1,users=0", want: []Target{{Name: "movie-service", Path: "/movies/", Weight: 1}}},
+		{mix: "users=0", wantErr: true},
+		{mix: "users=-1", wantErr: true},
+		{mix: "users=x", wantErr: true},
+		{mix: "tickets=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mix, func(t *testing.T) {
+			got, err := ParseMix(tt.mix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMix(%q) error = %v, wantErr %v", tt.mix, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMix(%q) = %v, want %v", tt.mix, got, tt.want)
+			}
+		})
+	}
+}
+
+// timeoutError is what http.Client returns when Client.Timeout expires
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "Client.Timeout exceeded while awaiting headers" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestBuildReportAccounting(t *testing.T) {
+	cfg := Config{BaseURL: "http://gateway", Targets: []Target{{Name: "movie-service", Path: "/movies/", Weight: 1}}}
+	results := []result{
+		{cluster: "ctx1", status: 200, latency: 10 * time.Millisecond},
+		{cluster: "ctx2", status: 200, latency: 30 * time.Millisecond},
+		{cluster: "ctx2", status: 503, latency: 20 * time.Millisecond},
+		// 요청 타임아웃: context.DeadlineExceeded 를 감싸도 실패로 집계
+		{cluster: NoCluster, err: fmt.Errorf("Get: %w", errors.Join(timeoutError{}, context.DeadlineExceeded)), latency: time.Second},
+		{cluster: NoCluster, err: errors.New("dial tcp: connect: connection refused")},
+		// 실행이 끝나 취소된 요청은 제외
+		{cluster: NoCluster, err: context.DeadlineExceeded, aborted: true},
+		{cluster: NoCluster, err: context.Canceled, aborted: true},
+	}
+
+	report := buildReport(cfg, time.Now(), time.Second, results)
+	tr := report.Targets[0]
+	if report.Requests != 5 || report.Failures != 2 || tr.Requests != 5 || tr.Failures != 2 {
+		t.Errorf("requests/failures = %d/%d (target %d/%d), want 5/2", report.Requests, report.Failures, tr.Requests, tr.Failures)
+	}
+	if want := map[string]int{"timeout": 1, "connection-refused": 1}; !reflect.DeepEqual(tr.Errors, want) {
+		t.Errorf("errors = %v, want %v", tr.Errors, want)
+	}
+	if want := map[string]int{"ctx1": 1, "ctx2": 2}; !reflect.DeepEqual(tr.Clusters, want) {
+		t.Errorf("clusters = %v, want %v", tr.Clusters, want)
+	}
+	if want := map[string]float64{"ctx1": 33.3, "ctx2": 66.7}; !reflect.DeepEqual(tr.ClusterPercent, want) {
+		t.Errorf("cluster percent = %v, want %v", tr.ClusterPercent, want)
+	}
+	if want := map[string]int{"200": 2, "503": 1}; !reflect.DeepEqual(tr.StatusCodes, want) {
+		t.Errorf("status codes = %v, want %v", tr.StatusCodes, want)
+	}
+	if tr.Latency.MinMs != 10 || tr.Latency.P50Ms != 20 || tr.Latency.MaxMs != 30 {
+		t.Errorf("latency = %+v, want min 10, p50 20, max 30", tr.Latency)
+	}
+}
+
+func TestRunCountsClientTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	report, err := Run(context.Background(), Config{
+		BaseURL:     server.URL,
+		Targets:     []Target{{Name: "slow", Path: "/", Weight: 1}},
+		Concurrency: 2,
+		Requests:    4,
+		Timeout:     50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Run error = %v", err)
+	}
+	tr := report.Targets[0]
+	if tr.Requests != 4 || tr.Failures != 4 || tr.Errors["timeout"] != 4 {
+		t.Errorf("requests %d, failures %d, errors %v; want 4 timeouts", tr.Requests, tr.Failures, tr.Errors)
+	}
+}
+
+func TestSchedulerSmoothWeights(t *testing.T) {
+	sched := newScheduler([]Target{{Name: "a", Weight: 2}, {Name: "b", Weight: 1}})
+	var got []int
+	for i := 0; i < 6; i++ {
+		got = append(got, sched.next())
+	}
+	if want := []int{0, 1, 0, 0, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestPercentileNearestRank(t *testing.T) {
+	ten := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		name   string
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{"empty", nil, 95, 0},
+		{"single", []float64{42}, 50, 42},
+		{"p0 is the minimum", ten, 0, 1},
+		{"p50 of ten", ten, 50, 5},
+		{"p51 rounds up", ten, 51, 6},
+		{"p90 of ten", ten, 90, 9},
+		{"p95 of ten", ten, 95, 10},
+		{"p100 is the maximum", ten, 100, 10},
+		{"p95 of twenty", []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}, 95, 19},
+		{"p50 of four", []float64{10, 20, 30, 40}, 50, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("Percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissedTicks(t *testing.T) {
+	interval := 100 * time.Millisecond
+	tests := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{elapsed: 100 * time.Millisecond, want: 0},
+		{elapsed: 130 * time.Millisecond, want: 0}, // 지연된 틱
+		{elapsed: 40 * time.Millisecond, want: 0},  // 첫 틱이 시작 직후
+		{elapsed: 200 * time.Millisecond, want: 1},
+		{elapsed: 480 * time.Millisecond, want: 4},
+	}
+	for _, tt := range tests {
+		if got := missedTicks(tt.elapsed, interval); got != tt.want {
+			t.Errorf("missedTicks(%v, %v) = %d, want %d", tt.elapsed, interval, got, tt.want)
+		}
+	}
+}
+
+func TestRunReportsSkippedTicks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	// 동시성 1, 요청당 50ms 이므로 초당 200 요청은 불가능
+	report, err := Run(context.Background(), Config{
+		BaseURL:     server.URL,
+		Targets:     []Target{{Name: "slow", Path: "/", Weight: 1}},
+		Rate:        200,
+		Concurrency: 1,
+		Duration:    300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Run error = %v", err)
+	}
+	if report.SkippedTicks == 0 {
+		t.Errorf("skipped ticks = 0 with %d requests at rate 200, want the missed ticks reported", report.Requests)
+	}
+	var text strings.Builder
+	report.WriteText(&text)
+	if !strings.Contains(text.String(), "Skipped ticks:") {
+		t.Errorf("text report does not mention the skipped ticks:\n%s", text.String())
+	}
+}
@@ -4,21 +4,26 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
+	"maps"
 	"math/big"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	istioclient "istio.io/client-go/pkg/clientset/versioned"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+
+	"msa-sample-01/pkg/platform"
 )
 
 // DeploymentInfo represents service deployment information for Kubernetes
@@ -29,20 +34,16 @@ type DeploymentInfo struct {
 	PodName     string `json:"podName"`
 	NodeName    string `json:"nodeName"`
 	Status      string `json:"status"`
+	Ready       string `json:"ready"`    // 준비된 컨테이너 수 / 전체
+	Restarts    int32  `json:"restarts"`
+	Age         string `json:"age"`
 	Port        string `json:"port"`
 	Icon        string `json:"icon"`
 	LastChecked string `json:"lastChecked"`
 }
 
-// TrafficWeight represents service traffic distribution
-type TrafficWeight struct {
-	UserServiceCtx1Weight    int
-	UserServiceCtx2Weight    int
-	MovieServiceCtx1Weight   int
-	MovieServiceCtx2Weight   int
-	BookingServiceCtx1Weight int
-	BookingServiceCtx2Weight int
-}
+// subsetWeights maps each weighted service to the weight of every subset (cluster) it routes to
+type subsetWeights map[string]map[string]int
 
 // TrafficHistory represents recent traffic routing decisions
 type TrafficHistory struct {
@@ -51,46 +52,10 @@ type TrafficHistory struct {
 	BookingServiceHistory []string `json:"bookingServiceHistory"`
 }
 
-var kubernetesClient *kubernetes.Clientset
-var istioClient *istioclient.Clientset
-var trafficWeights TrafficWeight
-var trafficHistory TrafficHistory
+var kubernetesClient kubernetes.Interface
+var istioClient istioclient.Interface
 var maxHistorySize = 10
 
-func init() {
-	// Kubernetes 클라이언트 초기화
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		log.Printf("Failed to get in-cluster config: %v", err)
-		return
-	}
-
-	kubernetesClient, err = kubernetes.NewForConfig(config)
-	if err != nil {
-		log.Printf("Failed to create Kubernetes client: %v", err)
-		return
-	}
-
-	// Istio 클라이언트 초기화
-	istioClient, err = istioclient.NewForConfig(config)
-	if err != nil {
-		log.Printf("Failed to create Istio client: %v", err)
-		return
-	}
-
-	// 트래픽 가중치 초기화 (환경변수 또는 기본값)
-	trafficWeights = TrafficWeight{
-		UserServiceCtx1Weight:    getEnvInt("USER_SERVICE_CTX1_WEIGHT", 70),
-		UserServiceCtx2Weight:    getEnvInt("USER_SERVICE_CTX2_WEIGHT", 30),
-		MovieServiceCtx1Weight:   getEnvInt("MOVIE_SERVICE_CTX1_WEIGHT", 30),
-		MovieServiceCtx2Weight:   getEnvInt("MOVIE_SERVICE_CTX2_WEIGHT", 70),
-		BookingServiceCtx1Weight: getEnvInt("BOOKING_SERVICE_CTX1_WEIGHT", 50),
-		BookingServiceCtx2Weight: getEnvInt("BOOKING_SERVICE_CTX2_WEIGHT", 50),
-	}
-
-	log.Printf("Traffic weights initialized: %+v", trafficWeights)
-}
-
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -100,192 +65,211 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
-// getVirtualServiceWeights reads actual weights from VirtualService resources
-func getVirtualServiceWeights() TrafficWeight {
-	weights := TrafficWeight{
-		UserServiceCtx1Weight:    70, // 기본값
-		UserServiceCtx2Weight:    30,
-		MovieServiceCtx1Weight:   30,
-		MovieServiceCtx2Weight:   70,
-		BookingServiceCtx1Weight: 50,
-		BookingServiceCtx2Weight: 50,
+// getVirtualServiceWeights reads the weighted route of every managed service through weightedRoute;
+// subsets defined by the service's DestinationRule but left out of the route count as 0.
+// Services whose VirtualService cannot be read keep the weights of their route config.
+func getVirtualServiceWeights() subsetWeights {
+	weights := subsetWeights{}
+	for _, route := range gatewayConfig.Routes {
+		if route.VirtualService != "" {
+			weights[route.Service] = maps.Clone(route.Weights)
+		}
 	}
 
 	if istioClient == nil {
-		log.Printf("Istio client not available, using default weights")
+		slog.Debug("Istio client not available, using configured weights")
 		return weights
 	}
 
-	// User Service VirtualService 조회
-	if userVS, err := istioClient.NetworkingV1().VirtualServices("theater-msa").Get(context.TODO(), "user-service-vs", metav1.GetOptions{}); err == nil {
-		if len(userVS.Spec.Http) > 1 && len(userVS.Spec.Http[1].Route) >= 2 {
-			// 카나리가 아닌 일반 라우팅 규칙에서 가중치 추출
-			for _, route := range userVS.Spec.Http[1].Route {
-				if route.Destination.Subset == "ctx1" {
-					weights.UserServiceCtx1Weight = int(route.Weight)
-				} else if route.Destination.Subset == "ctx2" {
-					weights.UserServiceCtx2Weight = int(route.Weight)
-				}
-			}
-			log.Printf("User service weights from VirtualService: ctx1=%d, ctx2=%d", weights.UserServiceCtx1Weight, weights.UserServiceCtx2Weight)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	subsets := destinationRuleSubsets(ctx)
+	for service := range weights {
+		vs, err := getServiceVirtualService(ctx, service)
+		if err != nil {
+			slog.Warn("Failed to get VirtualService", "target_service", service, "error", err)
+			continue
 		}
-	} else {
-		log.Printf("Failed to get user-service-vs: %v", err)
-	}
-
-	// Movie Service VirtualService 조회
-	if movieVS, err := istioClient.NetworkingV1().VirtualServices("theater-msa").Get(context.TODO(), "movie-service-vs", metav1.GetOptions{}); err == nil {
-		if len(movieVS.Spec.Http) > 1 && len(movieVS.Spec.Http[1].Route) >= 2 {
-			for _, route := range movieVS.Spec.Http[1].Route {
-				if route.Destination.Subset == "ctx1" {
-					weights.MovieServiceCtx1Weight = int(route.Weight)
-				} else if route.Destination.Subset == "ctx2" {
-					weights.MovieServiceCtx2Weight = int(route.Weight)
-				}
-			}
-			log.Printf("Movie service weights from VirtualService: ctx1=%d, ctx2=%d", weights.MovieServiceCtx1Weight, weights.MovieServiceCtx2Weight)
+		route, err := weightedRoute(vs)
+		if err != nil {
+			slog.Warn("VirtualService has no weighted route", "virtual_service", vs.Name, "error", err)
+			continue
 		}
-	} else {
-		log.Printf("Failed to get movie-service-vs: %v", err)
-	}
-
-	// Booking Service VirtualService 조회
-	if bookingVS, err := istioClient.NetworkingV1().VirtualServices("theater-msa").Get(context.TODO(), "booking-service-vs", metav1.GetOptions{}); err == nil {
-		if len(bookingVS.Spec.Http) > 1 && len(bookingVS.Spec.Http[1].Route) >= 2 {
-			for _, route := range bookingVS.Spec.Http[1].Route {
-				if route.Destination.Subset == "ctx1" {
-					weights.BookingServiceCtx1Weight = int(route.Weight)
-				} else if route.Destination.Subset == "ctx2" {
-					weights.BookingServiceCtx2Weight = int(route.Weight)
-				}
+		current := routeWeights(route)
+		for _, subset := range subsets[service] {
+			if _, ok := current[subset]; !ok {
+				current[subset] = 0
 			}
-			log.Printf("Booking service weights from VirtualService: ctx1=%d, ctx2=%d", weights.BookingServiceCtx1Weight, weights.BookingServiceCtx2Weight)
 		}
-	} else {
-		log.Printf("Failed to get booking-service-vs: %v", err)
+		weights[service] = current
+		slog.Debug("Service weights from VirtualService", "target_service", service, "weights", current)
 	}
-
 	return weights
 }
 
-// addToHistory adds a cluster selection to the history for a specific service
-func addToHistory(serviceType string, cluster string) {
-	switch serviceType {
-	case "user":
-		trafficHistory.UserServiceHistory = append(trafficHistory.UserServiceHistory, cluster)
-		if len(trafficHistory.UserServiceHistory) > maxHistorySize {
-			trafficHistory.UserServiceHistory = trafficHistory.UserServiceHistory[1:]
-		}
-	case "movie":
-		trafficHistory.MovieServiceHistory = append(trafficHistory.MovieServiceHistory, cluster)
-		if len(trafficHistory.MovieServiceHistory) > maxHistorySize {
-			trafficHistory.MovieServiceHistory = trafficHistory.MovieServiceHistory[1:]
-		}
-	case "booking":
-		trafficHistory.BookingServiceHistory = append(trafficHistory.BookingServiceHistory, cluster)
-		if len(trafficHistory.BookingServiceHistory) > maxHistorySize {
-			trafficHistory.BookingServiceHistory = trafficHistory.BookingServiceHistory[1:]
+// destinationRuleSubsets lists the subsets defined for each service by the DestinationRules of the namespace
+func destinationRuleSubsets(ctx context.Context) map[string][]string {
+	list, err := istioClient.NetworkingV1().DestinationRules(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		slog.Warn("Failed to list DestinationRules", "error", err)
+		return nil
+	}
+	subsets := map[string][]string{}
+	for _, dr := range list.Items {
+		// host 는 user-service 또는 user-service.theater-msa.svc.cluster.local
+		service, _, _ := strings.Cut(dr.Spec.Host, ".")
+		for _, subset := range dr.Spec.Subsets {
+			subsets[service] = append(subsets[service], subset.Name)
 		}
 	}
+	return subsets
 }
 
-// weightedServiceSelect selects service based on weight and records the decision
-func weightedServiceSelect(serviceType string, ctx1Weight, ctx2Weight int, ctx1Service, ctx2Service string) string {
-	total := ctx1Weight + ctx2Weight
+// pickSubset chooses a subset at random in proportion to its weight.
+// Traffic history is recorded from upstream responses, not from this pick.
+func pickSubset(service string, weights map[string]int) string {
+	subsets := slices.Sorted(maps.Keys(weights))
+	total := 0
+	for _, subset := range subsets {
+		total += weights[subset]
+	}
 	if total == 0 {
-		addToHistory(serviceType, "ctx1")
-		return ctx1Service // fallback
+		return ""
 	}
 
-	// Generate random number between 0 and total-1
 	randomNum, err := rand.Int(rand.Reader, big.NewInt(int64(total)))
 	if err != nil {
-		log.Printf("Failed to generate random number, falling back to ctx1: %v", err)
-		addToHistory(serviceType, "ctx1")
-		return ctx1Service
-	}
-
-	if randomNum.Int64() < int64(ctx1Weight) {
-		log.Printf("Selected %s (weight: %d/%d)", ctx1Service, ctx1Weight, total)
-		addToHistory(serviceType, "ctx1")
-		return ctx1Service
-	} else {
-		log.Printf("Selected %s (weight: %d/%d)", ctx2Service, ctx2Weight, total)
-		addToHistory(serviceType, "ctx2")
-		return ctx2Service
+		slog.Error("Failed to generate random number", "error", err)
+		return ""
 	}
-}
-
-// newReverseProxy creates a new reverse proxy for the target URL.
-func newReverseProxy(target string) *httputil.ReverseProxy {
-	targetURL, err := url.Parse(target)
-	if err != nil {
-		log.Fatalf("Failed to parse target URL %s: %v", target, err)
+	n := int(randomNum.Int64())
+	for _, subset := range subsets {
+		if n < weights[subset] {
+			slog.Debug("Selected cluster", "target_service", service, "selected", subset, "weight", weights[subset], "total", total)
+			return subset
+		}
+		n -= weights[subset]
 	}
-	return httputil.NewSingleHostReverseProxy(targetURL)
+	return ""
 }
 
 // customHandler handles routing between API calls and static files with weighted distribution
 func customHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Request: %s %s", r.Method, r.URL.Path)
-	
 	// API routes with weighted distribution
-	if strings.HasPrefix(r.URL.Path, "/users/") {
-		log.Printf("Routing to user-service via Istio VirtualService")
-		proxy := newReverseProxy("http://user-service:8081")
-		proxy.ServeHTTP(w, r)
-		return
-	}
-	
-	if strings.HasPrefix(r.URL.Path, "/movies/") {
-		log.Printf("Routing to movie-service via Istio VirtualService")
-		
+	if route, ok := gatewayConfig.routeForPath(r.URL.Path); ok {
+		slog.DebugContext(r.Context(), "Routing via Istio VirtualService", "target_service", route.Service)
+
 		// CTX2 지연 시뮬레이션을 위한 특별 처리
-		if os.Getenv("DELAY_INJECTION_MODE") == "true" {
+		if route.Service == "movie-service" && os.Getenv("DELAY_INJECTION_MODE") == "true" {
 			// 가중치 기반으로 CTX2로 라우팅될지 결정
-			currentWeights := getVirtualServiceWeights()
-			cluster := weightedServiceSelect("movie", currentWeights.MovieServiceCtx1Weight, currentWeights.MovieServiceCtx2Weight, "ctx1", "ctx2")
-			
+			cluster := pickSubset(route.Service, getVirtualServiceWeights()[route.Service])
+
 			if cluster == "ctx2" {
-				log.Printf("Simulating CTX2 delay: 5 seconds for movie service")
+				slog.InfoContext(r.Context(), "Simulating CTX2 delay for movie service", "delay", "5s")
+				_, span := tracer.Start(r.Context(), "simulated ctx2 delay", trace.WithAttributes(attribute.String("theater.cluster", cluster)))
 				time.Sleep(5 * time.Second)
+				span.End()
 			} else {
-				log.Printf("CTX1 routing: no delay for movie service")
+				slog.DebugContext(r.Context(), "CTX1 routing: no delay for movie service")
 			}
 		}
-		
-		proxy := newReverseProxy("http://movie-service:8082")
-		proxy.ServeHTTP(w, r)
+
+		if call, ok := grpcCallFor(route, r); ok {
+			slog.DebugContext(r.Context(), "Transcoding to gRPC", "target_service", route.Service, "grpc_method", call.method)
+			transcodeAndRecord(route, call, w, r)
+			return
+		}
+
+		proxy := newReverseProxy(route.Service, route.Upstream)
+		proxyAndRecord(proxy, w, r)
 		return
 	}
-	
-	if strings.HasPrefix(r.URL.Path, "/bookings/") {
-		log.Printf("Routing to booking-service via Istio VirtualService")
-		proxy := newReverseProxy("http://booking-service:8083")
-		proxy.ServeHTTP(w, r)
+
+	if strings.HasPrefix(r.URL.Path, "/admin/") {
+		slog.DebugContext(r.Context(), "Serving admin API")
+		adminHandler(w, r)
 		return
 	}
-	
+
+	if r.URL.Path == "/graphql" {
+		graphqlHandler(w, r)
+		return
+	}
+
+	if r.URL.Path == "/openapi.json" {
+		openAPIHandler(w, r)
+		return
+	}
+
+	if r.URL.Path == "/docs" || r.URL.Path == "/docs/" {
+		docsHandler(w, r)
+		return
+	}
+
+	if r.URL.Path == "/metrics" {
+		metricsHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == "/healthz" {
+		healthzHandler(w, r)
+		return
+	}
+
+	if r.URL.Path == "/readyz" {
+		readyzHandler(w, r)
+		return
+	}
+
+	if r.URL.Path == "/startupz" {
+		startupzHandler(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/deployment-status") {
-		log.Printf("Serving deployment status: %s", r.URL.Path)
+		slog.DebugContext(r.Context(), "Serving deployment status")
 		getDeploymentStatus(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/traffic-weights") {
-		log.Printf("Serving traffic weights: %s", r.URL.Path)
+		slog.DebugContext(r.Context(), "Serving traffic weights")
 		getTrafficWeights(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/traffic-stats") {
+		slog.DebugContext(r.Context(), "Serving traffic statistics")
+		getTrafficStats(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/traffic-drift") {
+		slog.DebugContext(r.Context(), "Serving traffic drift")
+		getTrafficDrift(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/traffic-history") {
-		log.Printf("Serving traffic history: %s", r.URL.Path)
+		slog.DebugContext(r.Context(), "Serving traffic history")
 		getTrafficHistory(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/events/ws") {
+		slog.DebugContext(r.Context(), "Opening event WebSocket")
+		eventWebSocketServer.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/events") {
+		slog.DebugContext(r.Context(), "Opening event stream")
+		serveEventStream(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/topology") {
-		log.Printf("Serving multi-cluster topology: %s", r.URL.Path)
+		slog.DebugContext(r.Context(), "Serving multi-cluster topology")
 		getMultiClusterTopology(w, r)
 		return
 	}
@@ -300,7 +284,7 @@ func getTrafficWeights(w http.ResponseWriter, r *http.Request) {
 	
 	// 실시간으로 VirtualService에서 가중치 조회
 	currentWeights := getVirtualServiceWeights()
-	log.Printf("Returning traffic weights: %+v", currentWeights)
+	slog.DebugContext(r.Context(), "Returning traffic weights", "weights", currentWeights)
 	
 	json.NewEncoder(w).Encode(currentWeights)
 }
@@ -308,355 +292,128 @@ func getTrafficWeights(w http.ResponseWriter, r *http.Request) {
 // getTrafficHistory returns recent traffic routing history
 func getTrafficHistory(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(trafficHistory)
-}
 
-// MultiClusterTopology represents the overall cluster topology
-type MultiClusterTopology struct {
-	Clusters    []ClusterInfo    `json:"clusters"`
-	Services    []ServiceInfo    `json:"services"`
-	TrafficFlow []TrafficFlowInfo `json:"trafficFlow"`
-	LastUpdated string           `json:"lastUpdated"`
-}
-
-// ClusterInfo represents cluster information
-type ClusterInfo struct {
-	Name     string `json:"name"`
-	Provider string `json:"provider"`
-	Status   string `json:"status"`
-	NodeCount int   `json:"nodeCount"`
-}
-
-// ServiceInfo represents service deployment across clusters
-type ServiceInfo struct {
-	Name        string            `json:"name"`
-	Icon        string            `json:"icon"`
-	Deployments map[string]string `json:"deployments"` // cluster -> pod name
-	Port        string            `json:"port"`
-}
-
-// TrafficFlowInfo represents traffic flow between services
-type TrafficFlowInfo struct {
-	From        string `json:"from"`
-	To          string `json:"to"`
-	Weight      int    `json:"weight"`
-	IsActive    bool   `json:"isActive"`
-	FlowType    string `json:"flowType"` // "internal", "cross-cluster"
+	history := TrafficHistory{
+		UserServiceHistory:    traffic.recentClusters("user-service", maxHistorySize),
+		MovieServiceHistory:   traffic.recentClusters("movie-service", maxHistorySize),
+		BookingServiceHistory: traffic.recentClusters("booking-service", maxHistorySize),
+	}
+	json.NewEncoder(w).Encode(history)
 }
 
-// getDeploymentStatus returns comprehensive multi-cluster deployment information
+// getDeploymentStatus returns the pods of every cluster; ?view=clusters groups them with per-cluster errors
 func getDeploymentStatus(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	
-	var allDeployments []DeploymentInfo
-	
-	// Get CTX1 pods (current cluster where API Gateway runs)
-	if kubernetesClient != nil {
-		pods, err := kubernetesClient.CoreV1().Pods("theater-msa").List(context.TODO(), metav1.ListOptions{})
-		if err == nil {
-			for _, pod := range pods.Items {
-				deployment := createDeploymentInfo(&pod)
-				if deployment.Service != "unknown" {
-					allDeployments = append(allDeployments, deployment)
-				}
-			}
-		}
+
+	statuses, err := collectDeploymentStatus(r.Context())
+	if statuses == nil {
+		platform.Error(w, r, http.StatusServiceUnavailable, codeKubeUnavailable, fmt.Sprintf("Deployment status unavailable: %v", err))
+		return
 	}
-	
-	// 한국 시간 설정
-	loc, err := time.LoadLocation("Asia/Seoul")
-	if err != nil {
-		loc = time.UTC // fallback to UTC if timezone loading fails
-		log.Printf("Failed to load Asia/Seoul timezone, using UTC: %v", err)
+
+	if r.URL.Query().Get("view") == "clusters" {
+		json.NewEncoder(w).Encode(statuses)
+		return
 	}
-	
-	// Add CTX2 services information (based on actual deployment status)
-	ctx2Services := []DeploymentInfo{
-		{
-			Service:     "User Service",
-			Cluster:     "ctx2",
-			Namespace:   "theater-msa",
-			PodName:     "user-service-ctx2-86b6c465dd-jskqp",
-			NodeName:    "suslmk-nhn-default-worker-node-0",
-			Status:      "Running",
-			Port:        "8081",
-			Icon:        "👤",
-			LastChecked: time.Now().In(loc).Format("2006-01-02 15:04:05"),
-		},
-		{
-			Service:     "Movie Service",
-			Cluster:     "ctx2",
-			Namespace:   "theater-msa",
-			PodName:     "movie-service-ctx2-5759dfddd4-87lhd",
-			NodeName:    "suslmk-nhn-default-worker-node-0",
-			Status:      "Running",
-			Port:        "8082",
-			Icon:        "🎬",
-			LastChecked: time.Now().In(loc).Format("2006-01-02 15:04:05"),
-		},
-		{
-			Service:     "Booking Service",
-			Cluster:     "ctx2",
-			Namespace:   "theater-msa",
-			PodName:     "booking-service-ctx2-55c64fc9bd-br8h4",
-			NodeName:    "suslmk-nhn-default-worker-node-0",
-			Status:      "Running",
-			Port:        "8083",
-			Icon:        "🎟️",
-			LastChecked: time.Now().In(loc).Format("2006-01-02 15:04:05"),
-		},
-		{
-			Service:     "Redis",
-			Cluster:     "ctx2",
-			Namespace:   "theater-msa",
-			PodName:     "redis-59644f49c7-j5wct",
-			NodeName:    "suslmk-nhn-default-worker-node-0",
-			Status:      "Running",
-			Port:        "6379",
-			Icon:        "💾",
-			LastChecked: time.Now().In(loc).Format("2006-01-02 15:04:05"),
-		},
+
+	allDeployments := []DeploymentInfo{}
+	for _, status := range statuses {
+		allDeployments = append(allDeployments, status.Deployments...)
 	}
-	
-	allDeployments = append(allDeployments, ctx2Services...)
-	
 	json.NewEncoder(w).Encode(allDeployments)
 }
 
-// createDeploymentInfo creates deployment info from pod
-func createDeploymentInfo(pod metav1.Object) DeploymentInfo {
-	// Pod 인터페이스에서 실제 Pod 객체로 타입 변환
-	actualPod, ok := pod.(*v1.Pod)
-	if !ok {
-		log.Printf("Failed to convert to Pod object")
-		return DeploymentInfo{Service: "unknown"}
+// createDeploymentInfo creates deployment info from a pod of the given cluster
+func createDeploymentInfo(pod *v1.Pod, cluster string) DeploymentInfo {
+	podName := pod.GetName()
+
+	// DestinationRule subset과 같은 cluster 레이블을 우선 사용
+	if label := pod.Labels["cluster"]; label != "" {
+		cluster = label
 	}
-	
-	podName := actualPod.GetName()
-	status := string(actualPod.Status.Phase)
-	nodeName := actualPod.Spec.NodeName // 실제 노드명 사용
-	
-	// 컨테이너 상태 확인
-	if actualPod.Status.Phase == v1.PodRunning {
-		allReady := true
-		for _, containerStatus := range actualPod.Status.ContainerStatuses {
-			if !containerStatus.Ready {
-				allReady = false
-				break
-			}
-		}
-		if !allReady {
-			status = "Not Ready"
+
+	ready := 0
+	var restarts int32
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Ready {
+			ready++
 		}
+		restarts += containerStatus.RestartCount
 	}
-	
-	// Determine service type and cluster
+
+	// Determine service type
 	serviceName := "unknown"
-	cluster := "ctx1" // API Gateway is in CTX1
 	port := "unknown"
 	icon := "❓"
-
-	if strings.Contains(podName, "user-service") {
-		serviceName = "User Service"
-		port = "8081"
-		icon = "👤"
-	} else if strings.Contains(podName, "movie-service") {
-		serviceName = "Movie Service"
-		port = "8082"
-		icon = "🎬"
-	} else if strings.Contains(podName, "booking-service") {
-		serviceName = "Booking Service"
-		port = "8083"
-		icon = "🎟️"
-	} else if strings.Contains(podName, "api-gateway") {
-		serviceName = "API Gateway"
-		port = "8080"
-		icon = "🌐"
-	} else if strings.Contains(podName, "redis") {
-		serviceName = "Redis"
-		port = "6379"
-		icon = "💾"
-	}
-
-	// 한국 시간 설정
-	loc, err := time.LoadLocation("Asia/Seoul")
-	if err != nil {
-		loc = time.UTC // fallback to UTC if timezone loading fails
-		log.Printf("Failed to load Asia/Seoul timezone, using UTC: %v", err)
+	if workload, ok := gatewayConfig.workloadForPod(pod.Labels["app"], podName); ok {
+		serviceName = workload.DisplayName
+		port = workload.Port
+		icon = workload.Icon
 	}
-	
+
 	return DeploymentInfo{
 		Service:     serviceName,
 		Cluster:     cluster,
-		Namespace:   actualPod.GetNamespace(),
+		Namespace:   pod.GetNamespace(),
 		PodName:     podName,
-		NodeName:    nodeName, // 실제 노드명 사용
-		Status:      status,   // 실제 상태 사용
+		NodeName:    pod.Spec.NodeName,
+		Status:      podStatus(pod),
+		Ready:       fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+		Restarts:    restarts,
+		Age:         formatAge(time.Since(pod.CreationTimestamp.Time)),
 		Port:        port,
 		Icon:        icon,
-		LastChecked: time.Now().In(loc).Format("2006-01-02 15:04:05"),
-	}
-}
-
-// getMultiClusterTopology returns comprehensive multi-cluster topology with traffic flows
-func getMultiClusterTopology(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	
-	// Get current VirtualService weights
-	weights := getVirtualServiceWeights()
-	
-	// Build topology data
-	topology := MultiClusterTopology{
-		Clusters: []ClusterInfo{
-			{
-				Name:     "ctx1",
-				Provider: "NaverCloud Platform",
-				Status:   "Active",
-				NodeCount: 1,
-			},
-			{
-				Name:     "ctx2", 
-				Provider: "NHN Cloud NKS",
-				Status:   "Active",
-				NodeCount: 1,
-			},
-		},
-		Services: []ServiceInfo{
-			{
-				Name: "api-gateway",
-				Icon: "🌐",
-				Deployments: map[string]string{
-					"ctx1": "api-gateway-xxx",
-				},
-				Port: "8080",
-			},
-			{
-				Name: "eastwest-gateway",
-				Icon: "🌉",
-				Deployments: map[string]string{
-					"ctx1": "eastwest-gateway-ctx1-xxx",
-					"ctx2": "eastwest-gateway-ctx2-xxx",
-				},
-				Port: "15443",
-			},
-			{
-				Name: "user-service",
-				Icon: "👤", 
-				Deployments: map[string]string{
-					"ctx1": "user-service-ctx1-xxx",
-					"ctx2": "user-service-ctx2-xxx",
-				},
-				Port: "8081",
-			},
-			{
-				Name: "movie-service",
-				Icon: "🎬",
-				Deployments: map[string]string{
-					"ctx1": "movie-service-ctx1-xxx",
-					"ctx2": "movie-service-ctx2-xxx", 
-				},
-				Port: "8082",
-			},
-			{
-				Name: "booking-service",
-				Icon: "🎟️",
-				Deployments: map[string]string{
-					"ctx1": "booking-service-ctx1-xxx",
-					"ctx2": "booking-service-ctx2-xxx",
-				},
-				Port: "8083",
-			},
-			{
-				Name: "redis",
-				Icon: "💾",
-				Deployments: map[string]string{
-					"ctx1": "redis-xxx",
-				},
-				Port: "6379",
-			},
-		},
-		TrafficFlow: []TrafficFlowInfo{
-			// External traffic to API Gateway
-			{
-				From:     "external",
-				To:       "api-gateway-ctx1", 
-				Weight:   100,
-				IsActive: true,
-				FlowType: "external",
-			},
-			// API Gateway to User Service (CTX1 - internal)
-			{
-				From:     "api-gateway-ctx1",
-				To:       "user-service-ctx1",
-				Weight:   weights.UserServiceCtx1Weight,
-				IsActive: weights.UserServiceCtx1Weight > 0,
-				FlowType: "internal",
-			},
-			// API Gateway to CTX1 eastwest-gateway for cross-cluster traffic
-			{
-				From:     "api-gateway-ctx1",
-				To:       "eastwest-gateway-ctx1", 
-				Weight:   weights.UserServiceCtx2Weight + weights.MovieServiceCtx2Weight + weights.BookingServiceCtx2Weight,
-				IsActive: (weights.UserServiceCtx2Weight + weights.MovieServiceCtx2Weight + weights.BookingServiceCtx2Weight) > 0,
-				FlowType: "internal",
-			},
-			// CTX1 eastwest-gateway to CTX2 eastwest-gateway
-			{
-				From:     "eastwest-gateway-ctx1",
-				To:       "eastwest-gateway-ctx2",
-				Weight:   weights.UserServiceCtx2Weight + weights.MovieServiceCtx2Weight + weights.BookingServiceCtx2Weight,
-				IsActive: (weights.UserServiceCtx2Weight + weights.MovieServiceCtx2Weight + weights.BookingServiceCtx2Weight) > 0,
-				FlowType: "cross-cluster",
-			},
-			// CTX2 eastwest-gateway to services
-			{
-				From:     "eastwest-gateway-ctx2",
-				To:       "user-service-ctx2", 
-				Weight:   weights.UserServiceCtx2Weight,
-				IsActive: weights.UserServiceCtx2Weight > 0,
-				FlowType: "internal",
-			},
-			{
-				From:     "eastwest-gateway-ctx2",
-				To:       "movie-service-ctx2",
-				Weight:   weights.MovieServiceCtx2Weight,
-				IsActive: weights.MovieServiceCtx2Weight > 0,
-				FlowType: "internal",
-			},
-			{
-				From:     "eastwest-gateway-ctx2",
-				To:       "booking-service-ctx2",
-				Weight:   weights.BookingServiceCtx2Weight, 
-				IsActive: weights.BookingServiceCtx2Weight > 0,
-				FlowType: "internal",
-			},
-			// API Gateway to CTX1 services (internal)
-			{
-				From:     "api-gateway-ctx1",
-				To:       "movie-service-ctx1",
-				Weight:   weights.MovieServiceCtx1Weight,
-				IsActive: weights.MovieServiceCtx1Weight > 0,
-				FlowType: "internal",
-			},
-			{
-				From:     "api-gateway-ctx1",
-				To:       "booking-service-ctx1",
-				Weight:   weights.BookingServiceCtx1Weight,
-				IsActive: weights.BookingServiceCtx1Weight > 0,
-				FlowType: "internal",
-			},
-		},
-		LastUpdated: time.Now().Format("2006-01-02 15:04:05"),
+		LastChecked: time.Now().In(gatewayConfig.loc()).Format("2006-01-02 15:04:05"),
 	}
-	
-	json.NewEncoder(w).Encode(topology)
 }
 
 func main() {
-	log.Println("Starting API Gateway with weighted traffic distribution...")
-	
-	http.HandleFunc("/", customHandler)
+	var contexts stringList
+	kubeconfig := flag.String("kubeconfig", "", "path to a kubeconfig file (default: in-cluster config, then $KUBECONFIG or ~/.kube/config)")
+	configPath := flag.String("config", getEnvString("GATEWAY_CONFIG", ""), "path to the gateway YAML config (namespace, routes, timezone); env overrides apply on top")
+	flag.Var(&contexts, "context", "kubeconfig context; the first is the local cluster, further ones are remote clusters (repeatable, e.g. --context ctx1 --context ctx2)")
+	flag.Parse()
+
+	platform.InitLogging("api-gateway", localClusterName())
+	slog.Info("Starting API Gateway with weighted traffic distribution")
+	cfg, err := loadGatewayConfig(*configPath)
+	if err != nil {
+		slog.Error("Failed to load gateway config", "error", err)
+		os.Exit(1)
+	}
+	gatewayConfig = cfg
+	gatewayConfig.logSummary()
+	if err := initOpenAPI(); err != nil {
+		slog.Error("Failed to load OpenAPI spec", "error", err)
+		os.Exit(1)
+	}
+	if err := initGraphQL(); err != nil {
+		slog.Error("Failed to load GraphQL schema", "error", err)
+		os.Exit(1)
+	}
+	telemetry := platform.NewTelemetry(platform.TelemetryOptions{
+		Name:      "api-gateway",
+		Cluster:   localClusterName(),
+		Namespace: gatewayConfig.Namespace,
+		Route:     metricsRoute,
+		Quiet:     isPolledPath,
+	})
+
+	initKubernetesClients(*kubeconfig, contexts)
+
+	handler := telemetry.Middleware(customHandler)
+	startEventWatchers()
+	startDriftDetector()
 	
-	log.Println("API Gateway is running on port 8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	slog.Info("API Gateway is running", "port", gatewayConfig.Port)
+	err = platform.Serve(gatewayConfig.Server.serveConfig(gatewayConfig.Port), handler, platform.ShutdownHooks{
+		Drain: func() { draining.Store(true) },
+		// 이벤트 스트림을 끝내야 Shutdown 이 기다리지 않음
+		Stop:     func(context.Context) { close(shutdownStarted) },
+		Cleanups: []func(context.Context) error{closeGRPCClients, telemetry.Shutdown},
+	})
+	if err != nil {
+		slog.Error("Could not start server", "error", err)
+		os.Exit(1)
+	}
 }
\ No newline at end of file
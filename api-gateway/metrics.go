@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+// metricsHandler serves /metrics from the default registry (Go runtime and process metrics included);
+// the RED metrics (service="api-gateway") come from platform.Telemetry like in the services
+var metricsHandler = promhttp.Handler()
+
+// 업스트림 응답 헤더(X-Service-Cluster)로 본 실제 라우팅 결과
+var (
+	upstreamRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "theater",
+		Name:      "gateway_upstream_requests_total",
+		Help:      "Proxied requests by upstream service, the cluster that served them and status.",
+	}, []string{"upstream", "cluster", "status"})
+
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "theater",
+		Name:      "gateway_upstream_request_duration_seconds",
+		Help:      "Latency of proxied requests by upstream service and serving cluster.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"upstream", "cluster"})
+)
+
+var tracer = otel.Tracer("msa-sample-01/api-gateway")
+
+// tracedTransport creates a client span per outbound call and injects the traceparent header
+var tracedTransport = otelhttp.NewTransport(http.DefaultTransport)
+
+// tracedHTTPClient is used for the gateway's own calls to the services
+var tracedHTTPClient = &http.Client{Transport: tracedTransport}
+
+// polledPaths are polled by the UI and probes; tracing them would bury the service traces
+var polledPaths = []string{"/metrics", "/healthz", "/readyz", "/startupz", "/events", "/traffic-", "/deployment-status"}
+
+// isPolledPath reports UI polling and probe endpoints, whose access logs are kept at debug level and are not traced
+func isPolledPath(path string) bool {
+	for _, prefix := range polledPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// metricsRoute maps a request path to a low-cardinality route label
+func metricsRoute(path string) string {
+	if route, ok := gatewayConfig.routeForPath(path); ok {
+		return route.PathPrefix
+	}
+	for _, prefix := range reservedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return prefix
+		}
+	}
+	return "static"
+}
+
+// observeUpstream records a proxied request in the upstream metrics
+func observeUpstream(record TrafficRecord) {
+	upstreamRequestsTotal.WithLabelValues(record.Service, record.Cluster, strconv.Itoa(record.StatusCode)).Inc()
+	upstreamRequestDuration.WithLabelValues(record.Service, record.Cluster).Observe(record.Latency.Seconds())
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// openapiValidation values, same as OPENAPI_VALIDATION of the services (pkg/platform)
+const (
+	validationOff     = "off"
+	validationRequest = "request" // 스펙과 다른 요청은 400
+	validationFull    = "full"    // 응답도 검증, 스펙과 다른 응답은 500 으로 교체
+)
+
+// Problem codes answered by the validation middleware
+const (
+	codeSchemaViolation         = "SCHEMA_VIOLATION"
+	codeResponseSchemaViolation = "RESPONSE_SCHEMA_VIOLATION"
+)
+
+// openapiSpec documents the admin API; services are documented by their own /openapi.json
+//
+//go:embed openapi.yaml
+var openapiSpec []byte
+
+// gatewayOpenAPI is the parsed admin spec, loaded at startup by initOpenAPI
+var gatewayOpenAPI struct {
+	doc    *openapi3.T
+	router routers.Router
+}
+
+// initOpenAPI parses the embedded admin spec; a broken spec is a build mistake, so startup fails
+func initOpenAPI() error {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(openapiSpec)
+	if err != nil {
+		return fmt.Errorf("openapi spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return fmt.Errorf("openapi spec: %w", err)
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return fmt.Errorf("openapi spec: %w", err)
+	}
+	gatewayOpenAPI.doc = doc
+	gatewayOpenAPI.router = router
+	return nil
+}
+
+// fetchServiceSpec reads /openapi.json of a routed service through the mesh
+func fetchServiceSpec(ctx context.Context, route RouteConfig) (*openapi3.T, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(route.Upstream, "/")+"/openapi.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := probeHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openapi.json: %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewLoader().LoadFromData(data)
+}
+
+// mergeComponents copies the components of src missing from dst; the first definition wins,
+// so the gateway's Problem (with upstream) is the one documented
+func mergeComponents[M ~map[string]V, V any](dst *M, src M) {
+	for name, value := range src {
+		if *dst == nil {
+			*dst = M{}
+		}
+		if _, ok := (*dst)[name]; !ok {
+			(*dst)[name] = value
+		}
+	}
+}
+
+// mergedOpenAPI combines the admin spec with the spec of every routed service.
+// Only paths the gateway routes to that service are kept; unreachable services are listed
+// in x-unavailable-services so the document is still served while a service is down.
+func mergedOpenAPI(ctx context.Context) *openapi3.T {
+	base := gatewayOpenAPI.doc
+	merged := &openapi3.T{
+		OpenAPI:    base.OpenAPI,
+		Info:       base.Info,
+		Servers:    openapi3.Servers{{URL: "/"}},
+		Tags:       append(openapi3.Tags{}, base.Tags...),
+		Paths:      openapi3.Paths{},
+		Components: &openapi3.Components{},
+	}
+	for path, item := range base.Paths {
+		merged.Paths[path] = item
+	}
+	mergeComponents(&merged.Components.Schemas, base.Components.Schemas)
+	mergeComponents(&merged.Components.Parameters, base.Components.Parameters)
+	mergeComponents(&merged.Components.Headers, base.Components.Headers)
+	mergeComponents(&merged.Components.RequestBodies, base.Components.RequestBodies)
+	mergeComponents(&merged.Components.Responses, base.Components.Responses)
+	mergeComponents(&merged.Components.SecuritySchemes, base.Components.SecuritySchemes)
+
+	routes := gatewayConfig.Routes
+	specs := make([]*openapi3.T, len(routes))
+	errs := make([]error, len(routes))
+	var wg sync.WaitGroup
+	for i, route := range routes {
+		wg.Add(1)
+		go func(i int, route RouteConfig) {
+			defer wg.Done()
+			specs[i], errs[i] = fetchServiceSpec(ctx, route)
+		}(i, route)
+	}
+	wg.Wait()
+
+	var unavailable []string
+	for i, route := range routes {
+		if errs[i] != nil {
+			slog.WarnContext(ctx, "Service OpenAPI spec unavailable", "target_service", route.Service, "error", errs[i])
+			unavailable = append(unavailable, route.Service)
+			continue
+		}
+		spec := specs[i]
+		for path, item := range spec.Paths {
+			if routed, ok := gatewayConfig.routeForPath(path); !ok || routed.Service != route.Service {
+				continue
+			}
+			if _, exists := merged.Paths[path]; exists {
+				continue
+			}
+			merged.Paths[path] = item
+		}
+		if spec.Components != nil {
+			mergeComponents(&merged.Components.Schemas, spec.Components.Schemas)
+			mergeComponents(&merged.Components.Parameters, spec.Components.Parameters)
+			mergeComponents(&merged.Components.Headers, spec.Components.Headers)
+			mergeComponents(&merged.Components.RequestBodies, spec.Components.RequestBodies)
+			mergeComponents(&merged.Components.Responses, spec.Components.Responses)
+			mergeComponents(&merged.Components.SecuritySchemes, spec.Components.SecuritySchemes)
+		}
+		for _, tag := range spec.Tags {
+			if merged.Tags.Get(tag.Name) == nil {
+				merged.Tags = append(merged.Tags, tag)
+			}
+		}
+	}
+	if len(unavailable) > 0 {
+		sort.Strings(unavailable)
+		merged.Extensions = map[string]interface{}{"x-unavailable-services": unavailable}
+	}
+	return merged
+}
+
+// openAPIHandler serves the merged spec at /openapi.json
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	encoded, err := json.Marshal(mergedOpenAPI(r.Context()))
+	if err != nil {
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeInternal, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(encoded)
+}
+
+// swaggerUIPage loads Swagger UI from a CDN; "Authorize" takes the ADMIN_TOKEN for /admin/ calls
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="ko">
+<head>
+  <meta charset="utf-8">
+  <title>Theater MSA API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "/openapi.json",
+      dom_id: "#swagger-ui",
+      deepLinking: true,
+      persistAuthorization: true,
+      displayRequestDuration: true,
+    });
+  </script>
+</body>
+</html>
+`
+
+// docsHandler serves Swagger UI for the merged spec at /docs
+func docsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, swaggerUIPage)
+}
+
+var validationOptions = &openapi3filter.Options{
+	// 스펙과 다른 상태 코드도 위반으로 처리 (오류는 default 응답으로 문서화)
+	IncludeResponseStatus: true,
+	// 토큰은 authorizeAdmin 이 검사
+	AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+}
+
+// bufferedResponse holds the response until it has been validated
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// withAdminValidation checks /admin/ calls against openapi.yaml when openapiValidation is enabled.
+// Paths or methods the spec does not describe are passed to the handler unchanged.
+func withAdminValidation(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mode := gatewayConfig.OpenAPIValidation
+		if mode == validationOff || gatewayOpenAPI.router == nil {
+			next(w, r)
+			return
+		}
+		route, pathParams, err := gatewayOpenAPI.router.FindRoute(r)
+		if err != nil {
+			next(w, r)
+			return
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    validationOptions,
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			slog.InfoContext(r.Context(), "Request rejected by OpenAPI validation", "route", route.Path, "error", validationDetail(err))
+			platform.Error(w, r, http.StatusBadRequest, codeSchemaViolation, validationDetail(err))
+			return
+		}
+		if mode != validationFull {
+			next(w, r)
+			return
+		}
+
+		buffered := &bufferedResponse{header: w.Header()}
+		next(buffered, r)
+		if buffered.status == 0 {
+			buffered.status = http.StatusOK
+		}
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 buffered.status,
+			Header:                 buffered.header,
+			Body:                   io.NopCloser(bytes.NewReader(buffered.body.Bytes())),
+			Options:                validationOptions,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Response rejected by OpenAPI validation", "route", route.Path, "status", buffered.status, "error", validationDetail(err))
+			platform.Error(w, r, http.StatusInternalServerError, codeResponseSchemaViolation, validationDetail(err))
+			return
+		}
+		w.WriteHeader(buffered.status)
+		w.Write(buffered.body.Bytes())
+	}
+}
+
+// validationDetail turns a kin-openapi error into one line naming the offending field
+func validationDetail(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		field := strings.Join(schemaErr.JSONPointer(), "/")
+		prefix := "body"
+		var requestErr *openapi3filter.RequestError
+		if errors.As(err, &requestErr) && requestErr.Parameter != nil {
+			prefix = requestErr.Parameter.In + " parameter " + requestErr.Parameter.Name
+		}
+		if field != "" {
+			return fmt.Sprintf("%s /%s: %s", prefix, field, schemaErr.Reason)
+		}
+		return fmt.Sprintf("%s: %s", prefix, schemaErr.Reason)
+	}
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.Error()
+	}
+	var responseErr *openapi3filter.ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.Reason
+	}
+	return err.Error()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// maxUpstreamDetailBytes bounds the upstream body copied into the detail of a converted error
+const maxUpstreamDetailBytes = 1024
+
+// Machine-readable error codes answered by the gateway itself; the shared ones are platform.Code*
+const (
+	codeForbidden           = "FORBIDDEN"
+	codeConflict            = "CONFLICT"
+	codeUnknownService      = "UNKNOWN_SERVICE"
+	codeInvalidWeights      = "INVALID_WEIGHTS"
+	codeRolloutConflict     = "ROLLOUT_CONFLICT"
+	codeUnknownScenario     = "UNKNOWN_SCENARIO"
+	codeAmbiguousScenario   = "AMBIGUOUS_SCENARIO"
+	codeIstioUnavailable    = "ISTIO_UNAVAILABLE"
+	codeKubeUnavailable     = "KUBERNETES_UNAVAILABLE"
+	codeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	codeUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+	codeUpstreamError       = "UPSTREAM_ERROR"
+)
+
+// errorResponse answers err with the status of statusForError and the matching code
+func errorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	platform.Error(w, r, statusForError(err), codeForError(err), err.Error())
+}
+
+// isTimeout reports dial/response timeouts of an upstream call
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// upstreamCode classifies an error status answered by or for an upstream (Envoy 503/504 included)
+func upstreamCode(status int) string {
+	switch status {
+	case http.StatusGatewayTimeout:
+		return codeUpstreamTimeout
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codeUpstreamUnavailable
+	}
+	return codeUpstreamError
+}
+
+// upstreamProblem describes a proxy call to service that got no response
+func upstreamProblem(r *http.Request, service string, err error) platform.Problem {
+	status, code := http.StatusBadGateway, codeUpstreamUnavailable
+	if isTimeout(err) {
+		status, code = http.StatusGatewayTimeout, codeUpstreamTimeout
+	}
+	problem := platform.NewProblem(r, status, code, fmt.Sprintf("%s: %v", service, err))
+	problem.Upstream = service
+	return problem
+}
+
+// isJSONContentType accepts application/json and any +json type (problem+json included)
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// rewriteUpstreamError replaces a non-JSON error body with problem+json: Envoy fault aborts,
+// "upstream connect error" and "upstream request timeout" are plain text the UI cannot parse.
+// The problem names the service and, when the pod answered, its cluster and pod.
+func rewriteUpstreamError(resp *http.Response, service, cluster string) error {
+	if resp.StatusCode < http.StatusBadRequest || resp.Request.Method == http.MethodHead ||
+		resp.Header.Get("Content-Encoding") != "" || isJSONContentType(resp.Header.Get("Content-Type")) {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxUpstreamDetailBytes))
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	problem := platform.NewProblem(resp.Request, resp.StatusCode, upstreamCode(resp.StatusCode), strings.TrimSpace(string(body)))
+	problem.Service = service
+	problem.Cluster = cluster
+	problem.Pod = resp.Header.Get("X-Pod-Name")
+	encoded, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(encoded))
+	resp.ContentLength = int64(len(encoded))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(encoded)))
+	resp.Header.Set("Content-Type", platform.ProblemContentType)
+	resp.Header.Set("X-Content-Type-Options", "nosniff")
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// decodeProblem checks the problem+json content type and decodes the body
+func decodeProblem(t *testing.T, header http.Header, body io.Reader) platform.Problem {
+	t.Helper()
+	if got := header.Get("Content-Type"); got != platform.ProblemContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, platform.ProblemContentType)
+	}
+	var problem platform.Problem
+	if err := json.NewDecoder(body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	return problem
+}
+
+func TestErrorResponse(t *testing.T) {
+	vsResource := schema.GroupResource{Group: "networking.istio.io", Resource: "virtualservices"}
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid weights", err: weightValidationError{"weights must sum to 100"}, wantStatus: http.StatusBadRequest, wantCode: codeInvalidWeights},
+		{name: "ambiguous scenario", err: fmt.Errorf("%w %q", errAmbiguousScenario, "fault"), wantStatus: http.StatusBadRequest, wantCode: codeAmbiguousScenario},
+		{name: "unknown service", err: fmt.Errorf("%w %q", errUnknownService, "ticket"), wantStatus: http.StatusNotFound, wantCode: codeUnknownService},
+		{name: "unknown scenario", err: fmt.Errorf("%w %q", errUnknownScenario, "ticket"), wantStatus: http.StatusNotFound, wantCode: codeUnknownScenario},
+		{name: "kubernetes not found", err: apierrors.NewNotFound(vsResource, "movie-service-vs"), wantStatus: http.StatusNotFound, wantCode: platform.CodeNotFound},
+		{name: "rollout conflict", err: fmt.Errorf("%w: rollout-1", errRolloutConflict), wantStatus: http.StatusConflict, wantCode: codeRolloutConflict},
+		{name: "chaos conflict", err: fmt.Errorf("%w: chaos-1", errChaosConflict), wantStatus: http.StatusConflict, wantCode: codeConflict},
+		{name: "resource version conflict", err: apierrors.NewConflict(vsResource, "movie-service-vs", errors.New("modified")), wantStatus: http.StatusConflict, wantCode: codeConflict},
+		{name: "forbidden", err: apierrors.NewForbidden(vsResource, "movie-service-vs", errors.New("rbac")), wantStatus: http.StatusForbidden, wantCode: codeForbidden},
+		{name: "istio unavailable", err: errIstioUnavailable, wantStatus: http.StatusServiceUnavailable, wantCode: codeIstioUnavailable},
+		{name: "other", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCode: platform.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/admin/traffic-weights/movie-service", nil)
+			w := httptest.NewRecorder()
+			errorResponse(w, r, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			problem := decodeProblem(t, w.Header(), w.Body)
+			if problem.Code != tt.wantCode || problem.Status != tt.wantStatus || problem.Type != platform.ProblemType(tt.wantCode) {
+				t.Errorf("problem = %+v, want code %s status %d", problem, tt.wantCode, tt.wantStatus)
+			}
+			if problem.Detail != tt.err.Error() || problem.Instance != "/admin/traffic-weights/movie-service" {
+				t.Errorf("detail, instance = %q, %q", problem.Detail, problem.Instance)
+			}
+		})
+	}
+}
+
+// timeoutError is a net.Error that timed out, like a dial or response header timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestUpstreamProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "connection refused", err: fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED), wantStatus: http.StatusBadGateway, wantCode: codeUpstreamUnavailable},
+		{name: "net timeout", err: fmt.Errorf("dial tcp: %w", timeoutError{}), wantStatus: http.StatusGatewayTimeout, wantCode: codeUpstreamTimeout},
+		{name: "deadline", err: context.DeadlineExceeded, wantStatus: http.StatusGatewayTimeout, wantCode: codeUpstreamTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := upstreamProblem(httptest.NewRequest(http.MethodGet, "/movies/", nil), "movie-service", tt.err)
+			if problem.Status != tt.wantStatus || problem.Code != tt.wantCode || problem.Upstream != "movie-service" {
+				t.Errorf("problem = %+v, want %d %s for movie-service", problem, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRewriteUpstreamError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		encoding    string
+		body        string
+		wantCode    string // 비어 있으면 응답을 바꾸지 않음
+	}{
+		{name: "envoy fault abort", status: http.StatusServiceUnavailable, contentType: "text/plain", body: "fault filter abort", wantCode: codeUpstreamUnavailable},
+		{name: "envoy timeout", status: http.StatusGatewayTimeout, contentType: "text/plain", body: "upstream request timeout", wantCode: codeUpstreamTimeout},
+		{name: "plain 500", status: http.StatusInternalServerError, body: "boom", wantCode: codeUpstreamError},
+		{name: "service problem", status: http.StatusNotFound, contentType: platform.ProblemContentType, body: `{"code":"USER_NOT_FOUND"}`},
+		{name: "json error", status: http.StatusBadRequest, contentType: "application/json; charset=utf-8", body: `{"error":"bad"}`},
+		{name: "compressed", status: http.StatusServiceUnavailable, contentType: "text/plain", encoding: "gzip", body: "\x1f\x8b"},
+		{name: "success", status: http.StatusOK, contentType: "text/plain", body: "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+				Request:    req,
+			}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.encoding != "" {
+				resp.Header.Set("Content-Encoding", tt.encoding)
+			}
+			resp.Header.Set("X-Pod-Name", "user-service-7d9f")
+
+			if err := rewriteUpstreamError(resp, "user-service", "ctx2"); err != nil {
+				t.Fatalf("rewriteUpstreamError() error = %v", err)
+			}
+			if tt.wantCode == "" {
+				if body, _ := io.ReadAll(resp.Body); string(body) != tt.body {
+					t.Errorf("body = %q, want the upstream body unchanged", body)
+				}
+				return
+			}
+			problem := decodeProblem(t, resp.Header, resp.Body)
+			want := platform.Problem{Code: tt.wantCode, Status: tt.status, Detail: tt.body, Service: "user-service", Cluster: "ctx2", Pod: "user-service-7d9f", Instance: "/users/42"}
+			if problem.Code != want.Code || problem.Status != want.Status || problem.Detail != want.Detail ||
+				problem.Service != want.Service || problem.Cluster != want.Cluster || problem.Pod != want.Pod || problem.Instance != want.Instance {
+				t.Errorf("problem = %+v, want %+v", problem, want)
+			}
+		})
+	}
+}
+
+func TestAdminUnknownEndpoint(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	r := httptest.NewRequest(http.MethodGet, "/admin/unknown", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	adminHandler(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+	if problem := decodeProblem(t, w.Header(), w.Body); problem.Code != platform.CodeNotFound || problem.Instance != "/admin/unknown" {
+		t.Errorf("problem = %+v, want NOT_FOUND for /admin/unknown", problem)
+	}
+}
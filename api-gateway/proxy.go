@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// proxyStartKey stores the time the gateway started proxying a request
+type proxyStartKey struct{}
+
+// newReverseProxy creates a reverse proxy for the target URL that records
+// the serving cluster reported by the upstream response headers.
+func newReverseProxy(service string, target string) *httputil.ReverseProxy {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		slog.Error("Failed to parse target URL", "upstream", target, "error", err)
+		os.Exit(1)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	proxy.Transport = tracedTransport
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		// 실제 응답한 파드가 설정한 헤더로 Istio 라우팅 결과를 기록
+		cluster := resp.Header.Get("X-Service-Cluster")
+		if cluster == "" {
+			cluster = "unknown"
+		}
+		serviceName := resp.Header.Get("X-Service-Name")
+		if serviceName == "" {
+			serviceName = service
+		}
+
+		// 클라이언트 span 에 실제 응답한 클러스터/파드 기록
+		span := trace.SpanFromContext(resp.Request.Context())
+		span.SetAttributes(
+			attribute.String("theater.service", serviceName),
+			attribute.String("theater.cluster", cluster),
+			attribute.String("theater.pod", resp.Header.Get("X-Pod-Name")),
+		)
+
+		// 게이트웨이가 이미 같은 값을 설정했으므로 중복 헤더 방지
+		resp.Header.Del(platform.RequestIDHeader)
+
+		recordTraffic(resp.Request, TrafficRecord{
+			Service:    serviceName,
+			Cluster:    cluster,
+			Pod:        resp.Header.Get("X-Pod-Name"),
+			StatusCode: resp.StatusCode,
+			Source:     RecordSourceResponse,
+		})
+		return rewriteUpstreamError(resp, serviceName, cluster)
+	}
+
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		slog.WarnContext(r.Context(), "Proxy error", "target_service", service, "path", r.URL.Path, "error", err)
+		problem := upstreamProblem(r, service, err)
+		recordTraffic(r, TrafficRecord{
+			Service:    service,
+			Cluster:    "unknown",
+			StatusCode: problem.Status,
+			Source:     RecordSourceProxyError,
+		})
+		platform.WriteProblem(w, problem)
+	}
+
+	return proxy
+}
+
+// proxyAndRecord proxies the request, marking the start time used for latency
+func proxyAndRecord(proxy http.Handler, w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), proxyStartKey{}, time.Now())
+	proxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// recordTraffic completes the record from the request, stores it and publishes a routing event
+func recordTraffic(r *http.Request, record TrafficRecord) {
+	start, ok := r.Context().Value(proxyStartKey{}).(time.Time)
+	if !ok {
+		start = time.Now()
+	}
+	record.Method = r.Method
+	record.Path = r.URL.Path
+	record.Latency = time.Since(start)
+	record.Timestamp = start
+	traffic.add(record)
+	observeUpstream(record)
+
+	events.publish(EventTypeRouting, RoutingEvent{
+		Service:    record.Service,
+		Cluster:    record.Cluster,
+		Pod:        record.Pod,
+		Method:     record.Method,
+		Path:       record.Path,
+		StatusCode: record.StatusCode,
+		LatencyMs:  float64(record.Latency.Microseconds()) / 1000,
+		Timestamp:  start.Format(time.RFC3339Nano),
+	})
+}
@@ -0,0 +1,590 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"msa-sample-01/api-gateway/loadgen"
+	"msa-sample-01/pkg/platform"
+)
+
+// EventTypeRollout is published whenever a rollout changes state or step
+const EventTypeRollout = "rollout"
+
+// rolloutKubeTimeout bounds each VirtualService read or write made by a rollout
+const rolloutKubeTimeout = 10 * time.Second
+
+// Rollout states
+const (
+	RolloutProgressing = "progressing"
+	RolloutPaused      = "paused"
+	RolloutSucceeded   = "succeeded"
+	RolloutRolledBack  = "rolled-back"
+	RolloutAborted     = "aborted"
+	RolloutFailed      = "failed"
+)
+
+// RolloutSLO is the service level objective checked after every step
+type RolloutSLO struct {
+	MaxErrorRate float64 `json:"maxErrorRate"` // 0.01 = 1%
+	MaxP95Ms     float64 `json:"maxP95Ms"`
+	MinSamples   int     `json:"minSamples"` // 표본이 부족하면 다음 주기까지 대기 (기본 20)
+	// MaxWaitRounds bounds the pause intervals a step waits for MinSamples before it is rolled back (default 5)
+	MaxWaitRounds int `json:"maxWaitRounds"`
+}
+
+// RolloutRequest is the body of POST /admin/rollouts
+type RolloutRequest struct {
+	Service      string     `json:"service"`
+	From         string     `json:"from"`
+	To           string     `json:"to"`
+	Steps        []int      `json:"steps"`
+	PauseSeconds int        `json:"pauseSeconds"`
+	SLO          RolloutSLO `json:"slo"`
+}
+
+// RolloutStepResult records the outcome of one step
+type RolloutStepResult struct {
+	Step       int     `json:"step"`
+	Weight     int     `json:"weight"`
+	StartedAt  string  `json:"startedAt"`
+	FinishedAt string  `json:"finishedAt,omitempty"`
+	Samples    int     `json:"samples"`
+	ErrorRate  float64 `json:"errorRate"`
+	P95Ms      float64 `json:"p95Ms"`
+	Passed     bool    `json:"passed"`
+	Message    string  `json:"message,omitempty"`
+}
+
+// Rollout is the externally visible state of a progressive rollout
+type Rollout struct {
+	ID             string              `json:"id"`
+	Service        string              `json:"service"`
+	From           string              `json:"from"`
+	To             string              `json:"to"`
+	Steps          []int               `json:"steps"`
+	PauseSeconds   int                 `json:"pauseSeconds"`
+	SLO            RolloutSLO          `json:"slo"`
+	Status         string              `json:"status"`
+	CurrentStep    int                 `json:"currentStep"`
+	CurrentWeight  int                 `json:"currentWeight"`
+	InitialWeights map[string]int      `json:"initialWeights"`
+	Results        []RolloutStepResult `json:"results"`
+	Message        string              `json:"message,omitempty"`
+	CreatedBy      string              `json:"createdBy"`
+	CreatedAt      string              `json:"createdAt"`
+	UpdatedAt      string              `json:"updatedAt"`
+}
+
+// rollout couples the rollout state with the channel used to control its goroutine
+type rollout struct {
+	mu      sync.Mutex
+	state   Rollout
+	control chan string
+}
+
+// rolloutController owns all rollouts started by this gateway instance
+type rolloutController struct {
+	mu       sync.Mutex
+	rollouts map[string]*rollout
+	starting map[string]bool // start 가 VirtualService 를 읽는 동안 서비스를 예약
+}
+
+var rollouts = &rolloutController{rollouts: map[string]*rollout{}, starting: map[string]bool{}}
+
+func (ro *rollout) snapshot() Rollout {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	state := ro.state
+	state.Results = append([]RolloutStepResult(nil), ro.state.Results...)
+	return state
+}
+
+// update mutates the state under lock and publishes the new snapshot
+func (ro *rollout) update(mutate func(state *Rollout)) {
+	ro.mu.Lock()
+	mutate(&ro.state)
+	ro.state.UpdatedAt = time.Now().Format(time.RFC3339)
+	ro.mu.Unlock()
+	events.publish(EventTypeRollout, ro.snapshot())
+}
+
+// newID returns a random identifier such as "ro-1a2b3c4d5e6f"
+func newID(prefix string) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
+	}
+	return prefix + "-" + hex.EncodeToString(b)
+}
+
+// validateRolloutRequest fills defaults and rejects inconsistent requests
+func validateRolloutRequest(req *RolloutRequest) error {
+	req.Service = normalizeServiceName(req.Service)
+	if route, ok := gatewayConfig.route(req.Service); !ok || route.VirtualService == "" {
+		return fmt.Errorf("%w %q", errUnknownService, req.Service)
+	}
+	if req.From == "" || req.To == "" || req.From == req.To {
+		return weightValidationError{"from and to must be two different subsets"}
+	}
+	if len(req.Steps) == 0 {
+		req.Steps = []int{10, 25, 50, 100}
+	}
+	previous := 0
+	for _, step := range req.Steps {
+		if step <= previous || step > 100 {
+			return weightValidationError{"steps must be strictly increasing values between 1 and 100"}
+		}
+		previous = step
+	}
+	if req.Steps[len(req.Steps)-1] != 100 {
+		return weightValidationError{"the last step must be 100"}
+	}
+	if req.PauseSeconds <= 0 {
+		req.PauseSeconds = 60
+	}
+	if req.SLO.MaxErrorRate <= 0 {
+		req.SLO.MaxErrorRate = 0.01
+	}
+	if req.SLO.MaxP95Ms <= 0 {
+		req.SLO.MaxP95Ms = 500
+	}
+	switch {
+	case req.SLO.MinSamples < 0:
+		return weightValidationError{"slo.minSamples must be positive"}
+	case req.SLO.MinSamples == 0:
+		// 표본 없이 승격되지 않도록 항상 최소 표본 수를 요구
+		req.SLO.MinSamples = 20
+	}
+	switch {
+	case req.SLO.MaxWaitRounds < 0:
+		return weightValidationError{"slo.maxWaitRounds must be positive"}
+	case req.SLO.MaxWaitRounds == 0:
+		req.SLO.MaxWaitRounds = 5
+	}
+	return nil
+}
+
+// start registers a new rollout and launches its controller goroutine; the service is reserved
+// under the lock and the VirtualService is read outside it, so a slow API server does not block other calls
+func (c *rolloutController) start(ctx context.Context, req RolloutRequest, actor string) (*rollout, error) {
+	// chaos.start 가 chaos → rollouts 순서로 잠그므로 rollouts 잠금 전에 확인
+	if active, ok := chaos.activeFor(req.Service); ok {
+		return nil, fmt.Errorf("%w: %s", errChaosConflict, active)
+	}
+
+	c.mu.Lock()
+	if active, ok := c.activeForLocked(req.Service); ok {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", errRolloutConflict, active)
+	}
+	c.starting[req.Service] = true
+	c.mu.Unlock()
+
+	ro, err := newRollout(ctx, req, actor)
+
+	c.mu.Lock()
+	delete(c.starting, req.Service)
+	if err == nil {
+		c.rollouts[ro.state.ID] = ro
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	go ro.run()
+	return ro, nil
+}
+
+// newRollout reads the current weights of the service, which are restored on rollback
+func newRollout(ctx context.Context, req RolloutRequest, actor string) (*rollout, error) {
+	ctx, cancel := context.WithTimeout(ctx, rolloutKubeTimeout)
+	defer cancel()
+
+	vs, err := getServiceVirtualService(ctx, req.Service)
+	if err != nil {
+		return nil, err
+	}
+	route, err := weightedRoute(vs)
+	if err != nil {
+		return nil, err
+	}
+	initial := routeWeights(route)
+	for _, subset := range []string{req.From, req.To} {
+		if _, ok := initial[subset]; !ok {
+			return nil, weightValidationError{fmt.Sprintf("subset %q is not routed by %s", subset, vs.Name)}
+		}
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	return &rollout{
+		control: make(chan string, 1),
+		state: Rollout{
+			ID:             newID("ro"),
+			Service:        req.Service,
+			From:           req.From,
+			To:             req.To,
+			Steps:          req.Steps,
+			PauseSeconds:   req.PauseSeconds,
+			SLO:            req.SLO,
+			Status:         RolloutProgressing,
+			CurrentWeight:  initial[req.To],
+			InitialWeights: initial,
+			Results:        []RolloutStepResult{},
+			CreatedBy:      actor,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		},
+	}, nil
+}
+
+// activeForLocked describes the rollout starting, progressing or paused for service
+func (c *rolloutController) activeForLocked(service string) (string, bool) {
+	if c.starting[service] {
+		return fmt.Sprintf("a rollout is starting for %s", service), true
+	}
+	for _, existing := range c.rollouts {
+		state := existing.snapshot()
+		if state.Service == service && (state.Status == RolloutProgressing || state.Status == RolloutPaused) {
+			return fmt.Sprintf("rollout %s is %s for %s", state.ID, state.Status, service), true
+		}
+	}
+	return "", false
+}
+
+// checkNoActiveRollout rejects manual weight changes, scenarios and chaos experiments that touch
+// a service under rollout: the controller would overwrite them, or restore its initial weights over them
+func checkNoActiveRollout(services ...string) error {
+	rollouts.mu.Lock()
+	defer rollouts.mu.Unlock()
+	for _, service := range services {
+		if active, ok := rollouts.activeForLocked(service); ok {
+			return fmt.Errorf("%w: %s", errRolloutConflict, active)
+		}
+	}
+	return nil
+}
+
+func (c *rolloutController) get(id string) (*rollout, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ro, ok := c.rollouts[id]
+	return ro, ok
+}
+
+func (c *rolloutController) list() []Rollout {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := make([]Rollout, 0, len(c.rollouts))
+	for _, ro := range c.rollouts {
+		list = append(list, ro.snapshot())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt < list[j].CreatedAt })
+	return list
+}
+
+// send delivers a control command ("pause", "resume", "abort") to the rollout goroutine
+func (ro *rollout) send(command string) error {
+	state := ro.snapshot()
+	if state.Status != RolloutProgressing && state.Status != RolloutPaused {
+		return fmt.Errorf("%w: rollout %s is %s", errRolloutConflict, state.ID, state.Status)
+	}
+	select {
+	case ro.control <- command:
+		return nil
+	default:
+		return fmt.Errorf("%w: rollout %s is busy, retry", errRolloutConflict, state.ID)
+	}
+}
+
+// run walks through the steps, verifying the SLO after each pause interval
+func (ro *rollout) run() {
+	state := ro.snapshot()
+	pause := time.Duration(state.PauseSeconds) * time.Second
+
+	for i, weight := range state.Steps {
+		if err := ro.setWeight(weight); err != nil {
+			ro.finish(RolloutFailed, fmt.Sprintf("failed to apply step %d: %v", i+1, err), false)
+			return
+		}
+
+		stepStart := time.Now()
+		ro.update(func(s *Rollout) {
+			s.CurrentStep = i + 1
+			s.CurrentWeight = weight
+			s.Results = append(s.Results, RolloutStepResult{Step: i + 1, Weight: weight, StartedAt: stepStart.Format(time.RFC3339)})
+		})
+
+		for round := 1; ; round++ {
+			if aborted := ro.wait(pause); aborted {
+				ro.finish(RolloutAborted, "aborted by operator", true)
+				return
+			}
+
+			result := ro.evaluate(stepStart)
+			if result.Samples < state.SLO.MinSamples {
+				if round >= state.SLO.MaxWaitRounds {
+					// 트래픽이 없는 카나리아를 무한히 기다리지 않고 롤백
+					ro.recordResult(result, "")
+					ro.finish(RolloutRolledBack, fmt.Sprintf("not enough samples at %d%%: %d of %d after %d intervals", weight, result.Samples, state.SLO.MinSamples, round), true)
+					return
+				}
+				ro.recordResult(result, fmt.Sprintf("waiting for more samples (%d of %d)", result.Samples, state.SLO.MinSamples))
+				continue
+			}
+
+			ro.recordResult(result, "")
+			if !result.Passed {
+				ro.finish(RolloutRolledBack, fmt.Sprintf("SLO violated at %d%%: %s", weight, result.Message), true)
+				return
+			}
+			break
+		}
+	}
+
+	ro.finish(RolloutSucceeded, fmt.Sprintf("promoted %s to 100%%", state.To), false)
+}
+
+// wait sleeps for the pause interval while handling control commands; it returns true on abort
+func (ro *rollout) wait(pause time.Duration) bool {
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return false
+		case command := <-ro.control:
+			switch command {
+			case "abort":
+				return true
+			case "pause":
+				ro.update(func(s *Rollout) { s.Status = RolloutPaused })
+				for command = range ro.control {
+					if command == "abort" {
+						return true
+					}
+					if command == "resume" {
+						break
+					}
+				}
+				ro.update(func(s *Rollout) { s.Status = RolloutProgressing })
+				// 재개 시 관측 구간을 처음부터 다시 측정
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(pause)
+			}
+		}
+	}
+}
+
+// evaluate checks the canary subset's error rate and p95 latency since the step started
+func (ro *rollout) evaluate(since time.Time) RolloutStepResult {
+	state := ro.snapshot()
+	return evaluateRolloutStep(traffic.since(since), state.Service, state.To, state.SLO)
+}
+
+// evaluateRolloutStep judges the records served by subset against the SLO; a step without
+// samples never passes, so a canary that receives no traffic is not promoted
+func evaluateRolloutStep(records []TrafficRecord, service, subset string, slo RolloutSLO) RolloutStepResult {
+	latencies := []float64{}
+	errors := 0
+	for _, record := range records {
+		if record.Service != service || record.Cluster != subset {
+			continue
+		}
+		latencies = append(latencies, record.LatencyMs)
+		if record.IsError() {
+			errors++
+		}
+	}
+	sort.Float64s(latencies)
+
+	result := RolloutStepResult{Samples: len(latencies)}
+	if len(latencies) == 0 {
+		result.Message = fmt.Sprintf("no requests served by %s", subset)
+		return result
+	}
+	result.ErrorRate = float64(errors) / float64(len(latencies))
+	result.P95Ms = loadgen.Percentile(latencies, 95)
+
+	var violations []string
+	if result.ErrorRate > slo.MaxErrorRate {
+		violations = append(violations, fmt.Sprintf("error rate %.2f%% > %.2f%%", result.ErrorRate*100, slo.MaxErrorRate*100))
+	}
+	if result.P95Ms > slo.MaxP95Ms {
+		violations = append(violations, fmt.Sprintf("p95 %.0fms > %.0fms", result.P95Ms, slo.MaxP95Ms))
+	}
+	result.Passed = len(violations) == 0
+	result.Message = strings.Join(violations, ", ")
+	return result
+}
+
+// recordResult updates the result of the current step
+func (ro *rollout) recordResult(result RolloutStepResult, note string) {
+	ro.update(func(s *Rollout) {
+		last := &s.Results[len(s.Results)-1]
+		last.Samples = result.Samples
+		last.ErrorRate = result.ErrorRate
+		last.P95Ms = result.P95Ms
+		last.Passed = result.Passed
+		last.Message = result.Message
+		if note != "" {
+			last.Message = note
+			return
+		}
+		last.FinishedAt = time.Now().Format(time.RFC3339)
+	})
+}
+
+// setWeight routes weight percent of traffic to the target subset and the rest to the source
+func (ro *rollout) setWeight(weight int) error {
+	state := ro.snapshot()
+	weights := map[string]int{}
+	for subset := range state.InitialWeights {
+		weights[subset] = 0
+	}
+	weights[state.To] = weight
+	weights[state.From] = 100 - weight
+	return ro.applyWeights(weights, "rollout-step")
+}
+
+// applyWeights writes the weights and records the change in the audit log
+func (ro *rollout) applyWeights(weights map[string]int, action string) error {
+	state := ro.snapshot()
+	ctx, cancel := context.WithTimeout(context.Background(), rolloutKubeTimeout)
+	defer cancel()
+	_, before, err := applyServiceWeights(ctx, state.Service, weights, "")
+
+	record := AuditRecord{
+		Actor:  "rollout-controller:" + state.ID,
+		Action: action,
+		Target: state.Service,
+		Before: before,
+		After:  weights,
+		Result: "success",
+	}
+	if err != nil {
+		record.Result = "failed"
+		record.ErrorMsg = err.Error()
+	}
+	audit.add(record)
+	return err
+}
+
+// finish sets the terminal status, restoring the initial weights when requested
+func (ro *rollout) finish(status, message string, restore bool) {
+	if restore {
+		state := ro.snapshot()
+		if err := ro.applyWeights(state.InitialWeights, "rollout-rollback"); err != nil {
+			status = RolloutFailed
+			message = fmt.Sprintf("%s; rollback failed: %v", message, err)
+		}
+	}
+	slog.Info("Rollout finished", "rollout", ro.snapshot().ID, "status", status, "message", message)
+	ro.update(func(s *Rollout) {
+		s.Status = status
+		s.Message = message
+		if restore {
+			s.CurrentWeight = s.InitialWeights[s.To]
+		}
+	})
+}
+
+// rolloutsHandler serves /admin/rollouts and /admin/rollouts/{id}[/abort|/pause|/resume]
+func rolloutsHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/rollouts"), "/")
+
+	if path == "" {
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(rollouts.list())
+		case http.MethodPost:
+			createRolloutHandler(w, r)
+		default:
+			platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	ro, ok := rollouts.get(parts[0])
+	if !ok {
+		platform.Error(w, r, http.StatusNotFound, platform.CodeNotFound, "Rollout not found")
+		return
+	}
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
+			return
+		}
+		json.NewEncoder(w).Encode(ro.snapshot())
+		return
+	}
+
+	command := parts[1]
+	if r.Method != http.MethodPost || (command != "abort" && command != "pause" && command != "resume") {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid rollout operation")
+		return
+	}
+	if err := ro.send(command); err != nil {
+		platform.Error(w, r, http.StatusConflict, codeRolloutConflict, err.Error())
+		return
+	}
+	audit.add(AuditRecord{
+		Actor:  adminActor(r),
+		Remote: r.RemoteAddr,
+		Action: "rollout-" + command,
+		Target: ro.snapshot().ID,
+		Result: "accepted",
+	})
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(ro.snapshot())
+}
+
+func createRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	var req RolloutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if err := validateRolloutRequest(&req); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	ro, err := rollouts.start(r.Context(), req, adminActor(r))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	state := ro.snapshot()
+	audit.add(AuditRecord{
+		Actor:  adminActor(r),
+		Remote: r.RemoteAddr,
+		Action: "rollout-create",
+		Target: state.Service,
+		After:  req,
+		Result: "started " + state.ID,
+	})
+
+	w.Header().Set("Location", "/admin/rollouts/"+state.ID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(state)
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateRolloutRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       RolloutRequest
+		wantSteps []int
+		wantErr   bool
+	}{
+		{
+			name:      "defaults",
+			req:       RolloutRequest{Service: "movie", From: "ctx1", To: "ctx2"},
+			wantSteps: []int{10, 25, 50, 100},
+		},
+		{
+			name:      "custom steps",
+			req:       RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", Steps: []int{50, 100}},
+			wantSteps: []int{50, 100},
+		},
+		{name: "unknown service", req: RolloutRequest{Service: "ticket-service", From: "ctx1", To: "ctx2"}, wantErr: true},
+		{name: "same subsets", req: RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx1"}, wantErr: true},
+		{name: "missing subset", req: RolloutRequest{Service: "movie-service", From: "ctx1"}, wantErr: true},
+		{name: "decreasing steps", req: RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", Steps: []int{50, 25, 100}}, wantErr: true},
+		{name: "step above 100", req: RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", Steps: []int{50, 150}}, wantErr: true},
+		{name: "last step below 100", req: RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", Steps: []int{10, 50}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			err := validateRolloutRequest(&req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRolloutRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if req.Service != "movie-service" {
+				t.Errorf("service = %q, want movie-service", req.Service)
+			}
+			if !reflect.DeepEqual(req.Steps, tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", req.Steps, tt.wantSteps)
+			}
+			if req.PauseSeconds != 60 || req.SLO.MaxErrorRate != 0.01 || req.SLO.MaxP95Ms != 500 {
+				t.Errorf("defaults = pause %d, slo %+v", req.PauseSeconds, req.SLO)
+			}
+		})
+	}
+
+	req := RolloutRequest{Service: "ticket-service", From: "ctx1", To: "ctx2"}
+	if err := validateRolloutRequest(&req); !errors.Is(err, errUnknownService) {
+		t.Errorf("unknown service error = %v, want %v", err, errUnknownService)
+	}
+}
+
+func TestEvaluateRolloutStep(t *testing.T) {
+	slo := RolloutSLO{MaxErrorRate: 0.1, MaxP95Ms: 100, MinSamples: 5}
+	served := func(cluster string, status int, latencyMs float64) TrafficRecord {
+		return TrafficRecord{Service: "movie-service", Cluster: cluster, StatusCode: status, LatencyMs: latencyMs}
+	}
+	repeat := func(n int, record TrafficRecord) []TrafficRecord {
+		records := make([]TrafficRecord, n)
+		for i := range records {
+			records[i] = record
+		}
+		return records
+	}
+
+	tests := []struct {
+		name        string
+		records     []TrafficRecord
+		wantSamples int
+		wantPassed  bool
+		wantMessage string
+	}{
+		{
+			name:        "no traffic on the canary",
+			records:     repeat(20, served("ctx1", 200, 10)),
+			wantSamples: 0,
+			wantPassed:  false,
+			wantMessage: "no requests served by ctx2",
+		},
+		{
+			name:        "other services are ignored",
+			records:     repeat(5, TrafficRecord{Service: "user-service", Cluster: "ctx2", StatusCode: 500}),
+			wantSamples: 0,
+			wantPassed:  false,
+			wantMessage: "no requests served by ctx2",
+		},
+		{
+			name:        "healthy canary",
+			records:     append(repeat(10, served("ctx2", 200, 20)), served("ctx1", 500, 900)),
+			wantSamples: 10,
+			wantPassed:  true,
+		},
+		{
+			name:        "error rate above the SLO",
+			records:     append(repeat(8, served("ctx2", 200, 20)), repeat(2, served("ctx2", 503, 20))...),
+			wantSamples: 10,
+			wantPassed:  false,
+			wantMessage: "error rate 20.00% > 10.00%",
+		},
+		{
+			name:        "4xx does not count as error",
+			records:     append(repeat(8, served("ctx2", 200, 20)), repeat(2, served("ctx2", 404, 20))...),
+			wantSamples: 10,
+			wantPassed:  true,
+		},
+		{
+			name:        "p95 above the SLO",
+			records:     append(repeat(18, served("ctx2", 200, 20)), repeat(2, served("ctx2", 200, 250))...),
+			wantSamples: 20,
+			wantPassed:  false,
+			wantMessage: "p95 250ms > 100ms",
+		},
+		{
+			name:        "both violated",
+			records:     repeat(5, served("ctx2", 500, 300)),
+			wantSamples: 5,
+			wantPassed:  false,
+			wantMessage: "error rate 100.00% > 10.00%, p95 300ms > 100ms",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRolloutStep(tt.records, "movie-service", "ctx2", slo)
+			if got.Samples != tt.wantSamples || got.Passed != tt.wantPassed || got.Message != tt.wantMessage {
+				t.Errorf("evaluateRolloutStep() = samples %d, passed %v, message %q; want %d, %v, %q",
+					got.Samples, got.Passed, got.Message, tt.wantSamples, tt.wantPassed, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidateRolloutRequestMinSamples(t *testing.T) {
+	tests := []struct {
+		name       string
+		minSamples int
+		want       int
+		wantErr    bool
+	}{
+		{"omitted uses the default", 0, 20, false},
+		{"explicit value is kept", 50, 50, false},
+		{"negative is rejected", -1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", SLO: RolloutSLO{MinSamples: tt.minSamples}}
+			err := validateRolloutRequest(&req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRolloutRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && req.SLO.MinSamples != tt.want {
+				t.Errorf("minSamples = %d, want %d", req.SLO.MinSamples, tt.want)
+			}
+		})
+	}
+
+	req := RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", SLO: RolloutSLO{MaxWaitRounds: -1}}
+	if err := validateRolloutRequest(&req); err == nil {
+		t.Error("negative maxWaitRounds was accepted")
+	}
+	req = RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2"}
+	if err := validateRolloutRequest(&req); err != nil || req.SLO.MaxWaitRounds != 5 {
+		t.Errorf("maxWaitRounds = %d (error %v), want the default 5", req.SLO.MaxWaitRounds, err)
+	}
+}
+
+// useTestControllers gives the test its own rollout controller and chaos runner
+func useTestControllers(t *testing.T) {
+	t.Helper()
+	previousRollouts, previousChaos := rollouts, chaos
+	rollouts = &rolloutController{rollouts: map[string]*rollout{}, starting: map[string]bool{}}
+	chaos = &chaosRunner{experiments: map[string]*experiment{}}
+	t.Cleanup(func() {
+		for _, ro := range rollouts.rollouts {
+			ro.send("abort")
+		}
+		for _, e := range chaos.experiments {
+			e.cancel()
+		}
+		rollouts, chaos = previousRollouts, previousChaos
+	})
+}
+
+// waitForRolloutStatus polls until the rollout reaches status
+func waitForRolloutStatus(t *testing.T, ro *rollout, status string) Rollout {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if state := ro.snapshot(); state.Status == status {
+			return state
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("rollout status = %s, want %s", ro.snapshot().Status, status)
+	return Rollout{}
+}
+
+// movieWeights reads the weighted route of movie-service-vs from the fake Istio client
+func movieWeights(t *testing.T) map[string]int {
+	t.Helper()
+	vs, err := getServiceVirtualService(context.Background(), "movie-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err := weightedRoute(vs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return routeWeights(route)
+}
+
+func TestRolloutBlocksConflictingChanges(t *testing.T) {
+	useFakeIstio(t)
+	useTestControllers(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	req := RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", PauseSeconds: 3600}
+	if err := validateRolloutRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	ro, err := rollouts.start(context.Background(), req, "test")
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	if state := ro.snapshot(); !reflect.DeepEqual(state.InitialWeights, map[string]int{"ctx1": 50, "ctx2": 50}) {
+		t.Errorf("initial weights = %v, want 50/50", state.InitialWeights)
+	}
+
+	if _, err := rollouts.start(context.Background(), req, "test"); !errors.Is(err, errRolloutConflict) {
+		t.Errorf("second rollout error = %v, want %v", err, errRolloutConflict)
+	}
+	if err := checkNoActiveRollout("user-service"); err != nil {
+		t.Errorf("checkNoActiveRollout(user-service) = %v, want nil", err)
+	}
+
+	// 수동 가중치 변경은 409
+	r := httptest.NewRequest(http.MethodPut, "/admin/traffic-weights/movie-service", strings.NewReader(`{"weights":{"ctx1":0,"ctx2":100}}`))
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	adminHandler(w, r)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), codeRolloutConflict) {
+		t.Errorf("weight update during rollout = %d %s, want 409 %s", w.Code, w.Body.String(), codeRolloutConflict)
+	}
+
+	// 같은 서비스의 장애 주입과 모든 서비스를 바꾸는 시나리오 실험도 409
+	for _, fault := range []ChaosFault{
+		{Service: "movie-service", Rule: []byte(`{"abort":{"percentage":10,"httpStatus":503}}`)},
+		{Scenario: "delay"},
+	} {
+		chaosReq := ChaosExperimentRequest{Fault: fault}
+		if err := validateChaosRequest(&chaosReq); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chaos.start(chaosReq, "test"); !errors.Is(err, errRolloutConflict) {
+			t.Errorf("chaos %+v during rollout error = %v, want %v", fault, err, errRolloutConflict)
+		}
+	}
+
+	if err := ro.send("abort"); err != nil {
+		t.Fatal(err)
+	}
+	waitForRolloutStatus(t, ro, RolloutAborted)
+	if got := movieWeights(t); !reflect.DeepEqual(got, map[string]int{"ctx1": 50, "ctx2": 50}) {
+		t.Errorf("weights after abort = %v, want the initial 50/50", got)
+	}
+	if err := checkNoActiveRollout("movie-service"); err != nil {
+		t.Errorf("checkNoActiveRollout after abort = %v, want nil", err)
+	}
+}
+
+func TestRolloutRejectedDuringChaos(t *testing.T) {
+	useFakeIstio(t)
+	useTestControllers(t)
+
+	chaosReq := ChaosExperimentRequest{
+		Fault:   ChaosFault{Service: "movie-service", Rule: []byte(`{"abort":{"percentage":10,"httpStatus":503}}`)},
+		StartAt: time.Now().Add(time.Hour).Format(time.RFC3339),
+	}
+	if err := validateChaosRequest(&chaosReq); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chaos.start(chaosReq, "test"); err != nil {
+		t.Fatalf("chaos start error = %v", err)
+	}
+
+	req := RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2"}
+	if err := validateRolloutRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rollouts.start(context.Background(), req, "test"); !errors.Is(err, errChaosConflict) {
+		t.Errorf("rollout during scheduled experiment error = %v, want %v", err, errChaosConflict)
+	}
+}
+
+func TestRolloutRollsBackWithoutSamples(t *testing.T) {
+	useFakeIstio(t)
+	useTestControllers(t)
+
+	req := RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", PauseSeconds: 1, SLO: RolloutSLO{MinSamples: 5, MaxWaitRounds: 2}}
+	if err := validateRolloutRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	ro, err := rollouts.start(context.Background(), req, "test")
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+
+	// 카나리아가 트래픽을 받지 못하면 maxWaitRounds 구간 뒤 롤백
+	state := waitForRolloutStatus(t, ro, RolloutRolledBack)
+	if !strings.Contains(state.Message, "not enough samples at 10%") {
+		t.Errorf("message = %q, want a not enough samples rollback", state.Message)
+	}
+	if len(state.Results) != 1 || state.Results[0].Passed {
+		t.Errorf("results = %+v, want one failed step", state.Results)
+	}
+	if got := movieWeights(t); !reflect.DeepEqual(got, map[string]int{"ctx1": 50, "ctx2": 50}) {
+		t.Errorf("weights after rollback = %v, want the initial 50/50", got)
+	}
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	networkingv1 "istio.io/client-go/pkg/apis/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// EventTypeScenario is published when a fault scenario is applied
+const EventTypeScenario = "scenario"
+
+// scenarioAnnotation marks resources with the scenario that last applied them
+const scenarioAnnotation = "theater-msa/scenario"
+
+// initialScenario is applied by the reset operation
+const initialScenario = "01-initial"
+
+var (
+	errUnknownScenario   = errors.New("unknown scenario")
+	errAmbiguousScenario = errors.New("ambiguous scenario")
+)
+
+// scenarioDirPattern matches practice directories such as 03-delay-fault
+var scenarioDirPattern = regexp.MustCompile(`^\d{2}-[a-z0-9-]+$`)
+
+// scenarioAliases maps fault-injection-demo.sh commands to scenario directories
+var scenarioAliases = map[string]string{
+	"reset": "01-initial",
+	"setup": "02-circuit-breaker",
+	"delay": "03-delay-fault",
+	"error": "04-error-fault",
+	"block": "05-block-fault",
+	"chaos": "99-scenarios",
+}
+
+// scenarioDescriptions overrides the description read from the YAML comments
+var scenarioDescriptions = map[string]string{
+	"01-initial":         "초기 상태 (Round Robin + 기본 트래픽 분산)",
+	"02-circuit-breaker": "Circuit Breaker 설정 적용",
+	"03-delay-fault":     "Movie Service CTX2 지연 장애",
+	"04-error-fault":     "User Service HTTP 500 오류 장애",
+	"05-block-fault":     "Booking Service CTX2 클러스터 차단",
+	"99-scenarios":       "다중 서비스 복합 장애",
+}
+
+// Scenario is a practice/ kustomization loaded as Istio resources
+type Scenario struct {
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	VirtualServices  []string `json:"virtualServices"`
+	DestinationRules []string `json:"destinationRules"`
+	Active           bool     `json:"active"`
+
+	virtualServices  []*networkingv1.VirtualService
+	destinationRules []*networkingv1.DestinationRule
+}
+
+// scenarioCatalog holds the scenarios loaded from SCENARIO_DIR
+type scenarioCatalog struct {
+	mu        sync.Mutex
+	dir       string
+	scenarios map[string]*Scenario
+	active    string
+}
+
+var scenarios = &scenarioCatalog{dir: getEnvString("SCENARIO_DIR", "scenarios")}
+
+func getEnvString(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// load reads every scenario directory; it is called lazily so a missing directory is reported per request
+func (c *scenarioCatalog) load() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read scenario directory %s: %w", c.dir, err)
+	}
+
+	loaded := map[string]*Scenario{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !scenarioDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		scenario, err := loadScenario(filepath.Join(c.dir, entry.Name()))
+		if err != nil {
+			slog.Warn("Skipping scenario", "scenario", entry.Name(), "error", err)
+			continue
+		}
+		loaded[scenario.Name] = scenario
+	}
+
+	c.scenarios = loaded
+	return nil
+}
+
+// loadScenario parses the kustomization and its VirtualService/DestinationRule resources
+func loadScenario(dir string) (*Scenario, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var kustomization struct {
+		Resources []string `json:"resources"`
+	}
+	if err := yaml.Unmarshal(data, &kustomization); err != nil {
+		return nil, fmt.Errorf("invalid kustomization: %w", err)
+	}
+
+	scenario := &Scenario{Name: filepath.Base(dir), Description: scenarioDescriptions[filepath.Base(dir)]}
+	for _, resource := range kustomization.Resources {
+		content, err := os.ReadFile(filepath.Join(dir, resource))
+		if err != nil {
+			return nil, err
+		}
+		if scenario.Description == "" {
+			scenario.Description = leadingComment(content)
+		}
+		if err := scenario.addResources(content); err != nil {
+			return nil, fmt.Errorf("%s: %w", resource, err)
+		}
+	}
+	return scenario, nil
+}
+
+// leadingComment returns the first comment line of a YAML file, used as the description
+func leadingComment(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line == "---" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+		return ""
+	}
+	return ""
+}
+
+// addResources decodes a multi-document YAML file into typed Istio objects
+func (s *Scenario) addResources(content []byte) error {
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		jsonData, err := yaml.YAMLToJSON(document)
+		if err != nil {
+			return err
+		}
+		if string(bytes.TrimSpace(jsonData)) == "null" {
+			continue
+		}
+
+		var meta metav1.TypeMeta
+		if err := json.Unmarshal(jsonData, &meta); err != nil {
+			return err
+		}
+
+		switch meta.Kind {
+		case "VirtualService":
+			vs := &networkingv1.VirtualService{}
+			if err := json.Unmarshal(jsonData, vs); err != nil {
+				return err
+			}
+			s.virtualServices = append(s.virtualServices, vs)
+			s.VirtualServices = append(s.VirtualServices, vs.Name)
+		case "DestinationRule":
+			dr := &networkingv1.DestinationRule{}
+			if err := json.Unmarshal(jsonData, dr); err != nil {
+				return err
+			}
+			s.destinationRules = append(s.destinationRules, dr)
+			s.DestinationRules = append(s.DestinationRules, dr.Name)
+		default:
+			return fmt.Errorf("unsupported kind %q", meta.Kind)
+		}
+	}
+}
+
+// find resolves "03-delay-fault", "03", "delay-fault" or "delay" to a scenario
+func (c *scenarioCatalog) find(name string) (*Scenario, error) {
+	if alias, ok := scenarioAliases[name]; ok {
+		name = alias
+	}
+	if scenario, ok := c.scenarios[name]; ok {
+		return scenario, nil
+	}
+	keys := make([]string, 0, len(c.scenarios))
+	for key := range c.scenarios {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var matches []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, name+"-") || strings.HasSuffix(key, "-"+name) {
+			matches = append(matches, key)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w %q", errUnknownScenario, name)
+	case 1:
+		return c.scenarios[matches[0]], nil
+	}
+	// 잘못 고르면 다른 시나리오의 DestinationRule 이 삭제되므로 추측하지 않음
+	return nil, fmt.Errorf("%w %q: matches %s", errAmbiguousScenario, name, strings.Join(matches, ", "))
+}
+
+// list returns all scenarios sorted by name with the active flag set
+func (c *scenarioCatalog) list() ([]Scenario, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	c.refreshActiveLocked()
+
+	list := make([]Scenario, 0, len(c.scenarios))
+	for _, scenario := range c.scenarios {
+		item := *scenario
+		item.Active = scenario.Name == c.active
+		list = append(list, item)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// refreshActiveLocked reads the scenario annotation from the live VirtualServices
+func (c *scenarioCatalog) refreshActiveLocked() {
+	if istioClient == nil {
+		return
+	}
+	active := ""
+	for _, name := range gatewayConfig.virtualServiceNames() {
+		vs, err := istioClient.NetworkingV1().VirtualServices(gatewayConfig.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return
+		}
+		current := vs.Annotations[scenarioAnnotation]
+		if current == "" || (active != "" && current != active) {
+			// 일부만 적용되었거나 수동 변경된 경우
+			c.active = ""
+			return
+		}
+		active = current
+	}
+	c.active = active
+}
+
+// apply creates or updates the scenario resources and removes DestinationRules owned by other scenarios
+func (c *scenarioCatalog) apply(ctx context.Context, name string) (*Scenario, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if istioClient == nil {
+		return nil, errIstioUnavailable
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	scenario, err := c.find(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNoActiveRollout(scenario.services()...); err != nil {
+		return nil, err
+	}
+
+	// 같은 host에 대한 DestinationRule 충돌을 막기 위해 다른 시나리오의 DR 삭제
+	wanted := map[string]bool{}
+	for _, dr := range scenario.destinationRules {
+		wanted[dr.Name] = true
+	}
+	for _, other := range c.scenarios {
+		for _, dr := range other.destinationRules {
+			if wanted[dr.Name] {
+				continue
+			}
+			err := istioClient.NetworkingV1().DestinationRules(gatewayConfig.Namespace).Delete(ctx, dr.Name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to delete destinationrule %s: %w", dr.Name, err)
+			}
+			wanted[dr.Name] = true // 중복 삭제 방지
+		}
+	}
+
+	for _, desired := range scenario.destinationRules {
+		if err := applyDestinationRule(ctx, desired.DeepCopy(), scenario.Name); err != nil {
+			return nil, err
+		}
+	}
+	for _, desired := range scenario.virtualServices {
+		if err := applyVirtualService(ctx, desired.DeepCopy(), scenario.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	c.active = scenario.Name
+	result := *scenario
+	result.Active = true
+	return &result, nil
+}
+
+// services lists the routed services whose VirtualService the scenario replaces
+func (s *Scenario) services() []string {
+	services := []string{}
+	for _, route := range gatewayConfig.Routes {
+		if route.VirtualService != "" && slices.Contains(s.VirtualServices, route.VirtualService) {
+			services = append(services, route.Service)
+		}
+	}
+	return services
+}
+
+func applyVirtualService(ctx context.Context, vs *networkingv1.VirtualService, scenario string) error {
+	client := istioClient.NetworkingV1().VirtualServices(gatewayConfig.Namespace)
+	vs.Namespace = gatewayConfig.Namespace
+	setScenarioAnnotation(&vs.ObjectMeta, scenario)
+
+	existing, err := client.Get(ctx, vs.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = client.Create(ctx, vs, metav1.CreateOptions{})
+	case err == nil:
+		vs.ResourceVersion = existing.ResourceVersion
+		_, err = client.Update(ctx, vs, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply virtualservice %s: %w", vs.Name, err)
+	}
+	return nil
+}
+
+func applyDestinationRule(ctx context.Context, dr *networkingv1.DestinationRule, scenario string) error {
+	client := istioClient.NetworkingV1().DestinationRules(gatewayConfig.Namespace)
+	dr.Namespace = gatewayConfig.Namespace
+	setScenarioAnnotation(&dr.ObjectMeta, scenario)
+
+	existing, err := client.Get(ctx, dr.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = client.Create(ctx, dr, metav1.CreateOptions{})
+	case err == nil:
+		dr.ResourceVersion = existing.ResourceVersion
+		_, err = client.Update(ctx, dr, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply destinationrule %s: %w", dr.Name, err)
+	}
+	return nil
+}
+
+func setScenarioAnnotation(meta *metav1.ObjectMeta, scenario string) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[scenarioAnnotation] = scenario
+}
+
+// scenariosHandler serves /admin/scenarios, /admin/scenarios/active,
+// /admin/scenarios/reset and /admin/scenarios/{name}/apply
+func scenariosHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/scenarios"), "/")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		list, err := scenarios.list()
+		if err != nil {
+			platform.Error(w, r, http.StatusInternalServerError, platform.CodeInternal, err.Error())
+			return
+		}
+		json.NewEncoder(w).Encode(list)
+
+	case path == "active" && r.Method == http.MethodGet:
+		list, err := scenarios.list()
+		if err != nil {
+			platform.Error(w, r, http.StatusInternalServerError, platform.CodeInternal, err.Error())
+			return
+		}
+		for _, scenario := range list {
+			if scenario.Active {
+				json.NewEncoder(w).Encode(scenario)
+				return
+			}
+		}
+		platform.Error(w, r, http.StatusNotFound, platform.CodeNotFound, "No scenario is fully applied")
+
+	case path == "reset" && r.Method == http.MethodPost:
+		applyScenarioHandler(w, r, initialScenario, "scenario-reset")
+
+	case strings.HasSuffix(path, "/apply") && r.Method == http.MethodPost:
+		applyScenarioHandler(w, r, strings.TrimSuffix(path, "/apply"), "scenario-apply")
+
+	default:
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid scenario operation")
+	}
+}
+
+func applyScenarioHandler(w http.ResponseWriter, r *http.Request, name string, action string) {
+	record := AuditRecord{
+		Actor:  adminActor(r),
+		Remote: r.RemoteAddr,
+		Action: action,
+		Target: name,
+		Result: "success",
+	}
+
+	scenario, err := scenarios.apply(r.Context(), name)
+	if err != nil {
+		record.Result = "failed"
+		record.ErrorMsg = err.Error()
+		audit.add(record)
+
+		errorResponse(w, r, err)
+		return
+	}
+
+	audit.add(record)
+	events.publish(EventTypeScenario, scenario)
+	json.NewEncoder(w).Encode(scenario)
+}
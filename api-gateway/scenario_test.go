@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	networkingv1 "istio.io/client-go/pkg/apis/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// useTestScenarios loads the practice/ scenarios into a fresh catalog
+func useTestScenarios(t *testing.T) {
+	t.Helper()
+	previous := scenarios
+	scenarios = &scenarioCatalog{dir: "../practice"}
+	t.Cleanup(func() { scenarios = previous })
+}
+
+func TestScenarioCatalogLoadsPractice(t *testing.T) {
+	useTestScenarios(t)
+	useFakeIstio(t)
+
+	list, err := scenarios.list()
+	if err != nil {
+		t.Fatalf("list() error = %v", err)
+	}
+	var names []string
+	for _, scenario := range list {
+		names = append(names, scenario.Name)
+		if len(scenario.VirtualServices) == 0 || scenario.Description == "" {
+			t.Errorf("scenario %s = %+v, want virtual services and a description", scenario.Name, scenario)
+		}
+	}
+	want := []string{"01-initial", "02-circuit-breaker", "03-delay-fault", "04-error-fault", "05-block-fault", "99-scenarios"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("scenarios = %v, want %v", names, want)
+	}
+}
+
+func TestScenarioFind(t *testing.T) {
+	useTestScenarios(t)
+	if err := scenarios.load(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		{name: "03-delay-fault", want: "03-delay-fault"},
+		{name: "03", want: "03-delay-fault"},
+		{name: "delay-fault", want: "03-delay-fault"},
+		{name: "delay", want: "03-delay-fault"},
+		{name: "reset", want: "01-initial"},
+		{name: "fault", wantErr: errAmbiguousScenario},
+		{name: "ticket", wantErr: errUnknownScenario},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario, err := scenarios.find(tt.name)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("find(%q) error = %v, want %v", tt.name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || scenario.Name != tt.want {
+				t.Errorf("find(%q) = %v, %v; want %s", tt.name, scenario, err, tt.want)
+			}
+		})
+	}
+}
+
+// postScenario calls the scenario admin API and returns the response
+func postScenario(t *testing.T, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, path, nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	adminHandler(w, r)
+	return w
+}
+
+// scenarioOf returns the scenario annotation of a VirtualService, or "" when it does not exist
+func scenarioOf(t *testing.T, name string) string {
+	t.Helper()
+	vs, err := istioClient.NetworkingV1().VirtualServices("theater-msa").Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get virtualservice %s: %v", name, err)
+	}
+	return vs.Annotations[scenarioAnnotation]
+}
+
+func destinationRuleExists(t *testing.T, name string) bool {
+	t.Helper()
+	_, err := istioClient.NetworkingV1().DestinationRules("theater-msa").Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func TestScenarioApplyAndReset(t *testing.T) {
+	useTestScenarios(t)
+	useFakeIstio(t)
+	useTestControllers(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	// 01-initial 의 DestinationRule 이 배포된 상태에서 시작
+	initialDR := &networkingv1.DestinationRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-service-dr", Namespace: "theater-msa"},
+		Spec:       networkingv1alpha3.DestinationRule{Host: "user-service"},
+	}
+	if _, err := istioClient.NetworkingV1().DestinationRules("theater-msa").Create(context.Background(), initialDR, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := postScenario(t, http.MethodPost, "/admin/scenarios/delay/apply")
+	if w.Code != http.StatusOK {
+		t.Fatalf("apply status = %d (%s), want 200", w.Code, w.Body.String())
+	}
+	var applied Scenario
+	if err := json.NewDecoder(w.Body).Decode(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied.Name != "03-delay-fault" || !applied.Active {
+		t.Errorf("applied = %+v, want active 03-delay-fault", applied)
+	}
+	for _, name := range []string{"user-service-vs", "movie-service-vs", "booking-service-vs"} {
+		if got := scenarioOf(t, name); got != "03-delay-fault" {
+			t.Errorf("%s scenario = %q, want 03-delay-fault", name, got)
+		}
+	}
+	// 같은 host 의 DestinationRule 충돌을 막기 위해 다른 시나리오의 DR 은 삭제
+	if destinationRuleExists(t, "user-service-dr") || !destinationRuleExists(t, "user-service-circuit-breaker") {
+		t.Error("apply did not replace user-service-dr with user-service-circuit-breaker")
+	}
+
+	w = postScenario(t, http.MethodGet, "/admin/scenarios/active")
+	if w.Code != http.StatusOK {
+		t.Fatalf("active status = %d, want 200", w.Code)
+	}
+
+	w = postScenario(t, http.MethodPost, "/admin/scenarios/reset")
+	if w.Code != http.StatusOK {
+		t.Fatalf("reset status = %d (%s), want 200", w.Code, w.Body.String())
+	}
+	if got := scenarioOf(t, "movie-service-vs"); got != "01-initial" {
+		t.Errorf("movie-service-vs scenario after reset = %q, want 01-initial", got)
+	}
+	if !destinationRuleExists(t, "user-service-dr") || destinationRuleExists(t, "user-service-circuit-breaker") {
+		t.Error("reset did not restore user-service-dr")
+	}
+
+	records := audit.list()
+	if last := records[len(records)-1]; last.Action != "scenario-reset" || last.Target != initialScenario || last.Result != "success" {
+		t.Errorf("last audit record = %+v, want a successful scenario-reset", last)
+	}
+}
+
+func TestScenarioApplyErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		noIstio    bool
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "unknown scenario", path: "/admin/scenarios/ticket/apply", wantStatus: http.StatusNotFound, wantCode: codeUnknownScenario},
+		{name: "ambiguous scenario", path: "/admin/scenarios/fault/apply", wantStatus: http.StatusBadRequest, wantCode: codeAmbiguousScenario},
+		{name: "istio unavailable", path: "/admin/scenarios/delay/apply", noIstio: true, wantStatus: http.StatusServiceUnavailable, wantCode: codeIstioUnavailable},
+		{name: "invalid operation", path: "/admin/scenarios/delay", wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestScenarios(t)
+			useFakeIstio(t)
+			t.Setenv("ADMIN_TOKEN", "secret")
+			if tt.noIstio {
+				istioClient = nil
+			}
+
+			w := postScenario(t, http.MethodPost, tt.path)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d (%s), want %d", w.Code, w.Body.String(), tt.wantStatus)
+			}
+			var problem platform.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestScenarioApplyDuringRollout(t *testing.T) {
+	useTestScenarios(t)
+	useFakeIstio(t)
+	useTestControllers(t)
+
+	req := RolloutRequest{Service: "movie-service", From: "ctx1", To: "ctx2", PauseSeconds: 3600}
+	if err := validateRolloutRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	ro, err := rollouts.start(context.Background(), req, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := scenarios.apply(context.Background(), "delay"); !errors.Is(err, errRolloutConflict) {
+		t.Errorf("apply during rollout error = %v, want %v", err, errRolloutConflict)
+	}
+	if got := scenarioOf(t, "movie-service-vs"); got != "" {
+		t.Errorf("movie-service-vs scenario = %q, want unchanged", got)
+	}
+
+	// 롤아웃이 초기 가중치를 되돌린 뒤에 가짜 Istio 클라이언트를 정리
+	if err := ro.send("abort"); err != nil {
+		t.Fatal(err)
+	}
+	waitForRolloutStatus(t, ro, RolloutAborted)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"msa-sample-01/api-gateway/loadgen"
+	"msa-sample-01/pkg/platform"
+)
+
+// Record sources distinguish upstream responses from gateway-side proxy failures
+const (
+	RecordSourceResponse   = "response"
+	RecordSourceProxyError = "proxy-error"
+)
+
+// TrafficRecord is a single routed request kept in the traffic store
+type TrafficRecord struct {
+	Service    string        `json:"service"`
+	Cluster    string        `json:"cluster"`
+	Pod        string        `json:"pod,omitempty"`
+	Method     string        `json:"method,omitempty"`
+	Path       string        `json:"path,omitempty"`
+	StatusCode int           `json:"statusCode"`
+	Latency    time.Duration `json:"-"`
+	LatencyMs  float64       `json:"latencyMs"`
+	Source     string        `json:"source"`
+	Timestamp  time.Time     `json:"timestamp"`
+}
+
+// IsError reports whether the record counts towards the error rate
+func (r TrafficRecord) IsError() bool {
+	return r.StatusCode >= 500
+}
+
+// ServiceClusterStats aggregates records of one service served by one cluster
+type ServiceClusterStats struct {
+	Service   string  `json:"service"`
+	Cluster   string  `json:"cluster"`
+	Requests  int     `json:"requests"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	Share     float64 `json:"share"` // 서비스 전체 요청 중 해당 클러스터 비율
+	P50Ms     float64 `json:"p50Ms"`
+	P95Ms     float64 `json:"p95Ms"`
+	P99Ms     float64 `json:"p99Ms"`
+}
+
+// TrafficStats is the /traffic-stats response body
+type TrafficStats struct {
+	GeneratedAt    string                           `json:"generatedAt"`
+	BufferCapacity int                              `json:"bufferCapacity"`
+	BufferedCount  int                              `json:"bufferedCount"`
+	OldestRecord   string                           `json:"oldestRecord,omitempty"`
+	Windows        map[string][]ServiceClusterStats `json:"windows"`
+}
+
+// statsWindows are the rolling windows reported by /traffic-stats
+var statsWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+}
+
+// trafficStore is a fixed-size ring buffer of traffic records safe for concurrent use
+type trafficStore struct {
+	mu      sync.RWMutex
+	records []TrafficRecord
+	next    int
+	full    bool
+}
+
+var traffic = newTrafficStore(getEnvInt("TRAFFIC_BUFFER_SIZE", 10000))
+
+func newTrafficStore(capacity int) *trafficStore {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &trafficStore{records: make([]TrafficRecord, capacity)}
+}
+
+// add stores the record, overwriting the oldest entry once the buffer is full
+func (s *trafficStore) add(record TrafficRecord) {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	record.LatencyMs = float64(record.Latency.Microseconds()) / 1000
+
+	s.mu.Lock()
+	s.records[s.next] = record
+	s.next = (s.next + 1) % len(s.records)
+	if s.next == 0 {
+		s.full = true
+	}
+	s.mu.Unlock()
+}
+
+// since returns records newer than t in chronological order
+func (s *trafficStore) since(t time.Time) []TrafficRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// 요청 시작 시각 기준이므로 버퍼 순서와 완전히 일치하지 않아 전체를 검사한다
+	result := []TrafficRecord{}
+	for _, record := range s.orderedLocked() {
+		if record.Timestamp.After(t) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// recentClusters returns the last n clusters recorded for the service
+func (s *trafficStore) recentClusters(service string, n int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ordered := s.orderedLocked()
+	clusters := []string{}
+	for i := len(ordered) - 1; i >= 0 && len(clusters) < n; i-- {
+		if ordered[i].Service == service {
+			clusters = append(clusters, ordered[i].Cluster)
+		}
+	}
+	// 오래된 순서로 반환
+	for i, j := 0, len(clusters)-1; i < j; i, j = i+1, j-1 {
+		clusters[i], clusters[j] = clusters[j], clusters[i]
+	}
+	return clusters
+}
+
+// orderedLocked returns a view of the buffer from oldest to newest; caller holds the lock
+func (s *trafficStore) orderedLocked() []TrafficRecord {
+	if !s.full {
+		return s.records[:s.next]
+	}
+	ordered := make([]TrafficRecord, 0, len(s.records))
+	ordered = append(ordered, s.records[s.next:]...)
+	return append(ordered, s.records[:s.next]...)
+}
+
+// oldest returns the timestamp of the oldest buffered record
+func (s *trafficStore) oldest() (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.full {
+		return s.records[s.next].Timestamp, true
+	}
+	if s.next == 0 {
+		return time.Time{}, false
+	}
+	return s.records[0].Timestamp, true
+}
+
+func (s *trafficStore) capacity() int {
+	return len(s.records)
+}
+
+func (s *trafficStore) count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.full {
+		return len(s.records)
+	}
+	return s.next
+}
+
+// aggregate computes per service/cluster statistics for the given records
+func aggregate(records []TrafficRecord) []ServiceClusterStats {
+	type key struct{ service, cluster string }
+	latencies := map[key][]float64{}
+	errors := map[key]int{}
+	serviceTotals := map[string]int{}
+
+	for _, r := range records {
+		k := key{r.Service, r.Cluster}
+		latencies[k] = append(latencies[k], r.LatencyMs)
+		if r.IsError() {
+			errors[k]++
+		}
+		serviceTotals[r.Service]++
+	}
+
+	stats := make([]ServiceClusterStats, 0, len(latencies))
+	for k, values := range latencies {
+		sort.Float64s(values)
+		requests := len(values)
+		stats = append(stats, ServiceClusterStats{
+			Service:   k.service,
+			Cluster:   k.cluster,
+			Requests:  requests,
+			Errors:    errors[k],
+			ErrorRate: float64(errors[k]) / float64(requests),
+			Share:     float64(requests) / float64(serviceTotals[k.service]),
+			P50Ms:     loadgen.Percentile(values, 50),
+			P95Ms:     loadgen.Percentile(values, 95),
+			P99Ms:     loadgen.Percentile(values, 99),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Service != stats[j].Service {
+			return stats[i].Service < stats[j].Service
+		}
+		return stats[i].Cluster < stats[j].Cluster
+	})
+	return stats
+}
+
+// windowRecords returns the records of the rolling window ending now
+func windowRecords(window time.Duration) []TrafficRecord {
+	return traffic.since(time.Now().Add(-window))
+}
+
+// windowStats aggregates the records of a single rolling window ending now
+func windowStats(window time.Duration) []ServiceClusterStats {
+	return aggregate(windowRecords(window))
+}
+
+// getTrafficStats returns rolling-window traffic aggregates; ?window=1m limits the output
+func getTrafficStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	requested := r.URL.Query().Get("window")
+	result := TrafficStats{
+		GeneratedAt:    time.Now().Format(time.RFC3339),
+		BufferCapacity: traffic.capacity(),
+		BufferedCount:  traffic.count(),
+		Windows:        map[string][]ServiceClusterStats{},
+	}
+
+	if oldest, ok := traffic.oldest(); ok {
+		result.OldestRecord = oldest.Format(time.RFC3339)
+	}
+
+	for _, window := range statsWindows {
+		if requested != "" && requested != window.Name {
+			continue
+		}
+		result.Windows[window.Name] = windowStats(window.Duration)
+	}
+
+	if len(result.Windows) == 0 {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Unknown window, use 1m, 5m or 15m")
+		return
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrafficStoreWraparound(t *testing.T) {
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		capacity int
+		adds     int
+		want     []int // 남아 있는 기록의 순번, 오래된 순
+	}{
+		{"empty", 3, 0, []int{}},
+		{"partial", 3, 2, []int{0, 1}},
+		{"exactly full", 3, 3, []int{0, 1, 2}},
+		{"wrapped once", 3, 4, []int{1, 2, 3}},
+		{"wrapped twice", 3, 7, []int{4, 5, 6}},
+		{"capacity one", 1, 5, []int{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTrafficStore(tt.capacity)
+			for i := 0; i < tt.adds; i++ {
+				store.add(TrafficRecord{Service: "user-service", StatusCode: i, Timestamp: base.Add(time.Duration(i) * time.Second)})
+			}
+
+			got := []int{}
+			for _, record := range store.since(base.Add(-time.Second)) {
+				got = append(got, record.StatusCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("since() = %v, want %v", got, tt.want)
+			}
+			if count := store.count(); count != len(tt.want) {
+				t.Errorf("count() = %d, want %d", count, len(tt.want))
+			}
+
+			oldest, ok := store.oldest()
+			if len(tt.want) == 0 {
+				if ok {
+					t.Errorf("oldest() = %v, want none", oldest)
+				}
+				return
+			}
+			if want := base.Add(time.Duration(tt.want[0]) * time.Second); !ok || !oldest.Equal(want) {
+				t.Errorf("oldest() = %v, %v, want %v", oldest, ok, want)
+			}
+		})
+	}
+}
+
+func TestTrafficStoreRecentClusters(t *testing.T) {
+	store := newTrafficStore(4)
+	for _, record := range []TrafficRecord{
+		{Service: "user-service", Cluster: "ctx1"},
+		{Service: "movie-service", Cluster: "ctx2"},
+		{Service: "user-service", Cluster: "ctx2"},
+		{Service: "user-service", Cluster: "ctx1"},
+		{Service: "user-service", Cluster: "ctx2"}, // 첫 기록을 덮어씀
+	} {
+		store.add(record)
+	}
+
+	if got, want := store.recentClusters("user-service", 10), []string{"ctx2", "ctx1", "ctx2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recentClusters(10) = %v, want %v", got, want)
+	}
+	if got, want := store.recentClusters("user-service", 2), []string{"ctx1", "ctx2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recentClusters(2) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// eastWestGatewaySelector selects the Istio east-west gateway pods
+const eastWestGatewaySelector = "istio=eastwestgateway"
+
+// MultiClusterTopology represents the overall cluster topology
+type MultiClusterTopology struct {
+	Clusters    []ClusterInfo     `json:"clusters"`
+	Services    []ServiceInfo     `json:"services"`
+	TrafficFlow []TrafficFlowInfo `json:"trafficFlow"`
+	Errors      []string          `json:"errors,omitempty"`
+	LastUpdated string            `json:"lastUpdated"`
+}
+
+// ClusterInfo represents cluster information
+type ClusterInfo struct {
+	Name      string `json:"name"`
+	Provider  string `json:"provider"`
+	Region    string `json:"region,omitempty"`
+	Status    string `json:"status"`
+	NodeCount int    `json:"nodeCount"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ServiceInfo represents service deployment across clusters
+type ServiceInfo struct {
+	Name        string            `json:"name"`
+	Icon        string            `json:"icon"`
+	Deployments map[string]string `json:"deployments"` // cluster -> pod name
+	Replicas    map[string]string `json:"replicas"`    // cluster -> ready/total
+	Port        string            `json:"port"`
+}
+
+// TrafficFlowInfo represents traffic flow between services
+type TrafficFlowInfo struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Weight   int    `json:"weight"`
+	IsActive bool   `json:"isActive"`
+	FlowType string `json:"flowType"` // "external", "internal", "cross-cluster"
+}
+
+// clusterInventory is what was discovered in one cluster
+type clusterInventory struct {
+	info     ClusterInfo
+	pods     []v1.Pod // 샘플 네임스페이스 파드
+	gateways []v1.Pod // east-west gateway 파드
+	ports    map[string]string
+}
+
+// discoverCluster lists the nodes, workloads, services and east-west gateways of one cluster
+func discoverCluster(ctx context.Context, member memberCluster) clusterInventory {
+	inventory := clusterInventory{
+		info:  ClusterInfo{Name: member.Name, Provider: "unknown", Status: "Active"},
+		ports: map[string]string{},
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	core := member.Client.CoreV1()
+	nodes, err := core.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		inventory.info.Status = "Unreachable"
+		inventory.info.Error = err.Error()
+		return inventory
+	}
+	inventory.info.NodeCount = len(nodes.Items)
+	if len(nodes.Items) > 0 {
+		node := nodes.Items[0]
+		if scheme, _, found := strings.Cut(node.Spec.ProviderID, "://"); found && scheme != "" {
+			inventory.info.Provider = scheme
+		}
+		inventory.info.Region = node.Labels["topology.kubernetes.io/region"]
+	}
+
+	if pods, err := core.Pods(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.pods = pods.Items
+	} else {
+		inventory.info.Error = fmt.Sprintf("pods: %v", err)
+	}
+	if gateways, err := core.Pods("istio-system").List(ctx, metav1.ListOptions{LabelSelector: eastWestGatewaySelector}); err == nil {
+		inventory.gateways = gateways.Items
+	}
+	if services, err := core.Services(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, service := range services.Items {
+			if len(service.Spec.Ports) > 0 {
+				inventory.ports[service.Name] = fmt.Sprint(service.Spec.Ports[0].Port)
+			}
+		}
+	}
+	return inventory
+}
+
+// workloadName returns the app label of a pod, which is also the Kubernetes service name
+func workloadName(pod *v1.Pod) string {
+	if app := pod.Labels["app"]; app != "" {
+		return app
+	}
+	return pod.GenerateName
+}
+
+// podCluster prefers the cluster label used by the DestinationRule subsets
+func podCluster(pod *v1.Pod, member string) string {
+	if cluster := pod.Labels["cluster"]; cluster != "" {
+		return cluster
+	}
+	return member
+}
+
+// buildServices groups the discovered pods per workload and cluster
+func buildServices(inventories []clusterInventory) []ServiceInfo {
+	byName := map[string]*ServiceInfo{}
+	ready := map[string]map[string][2]int{}
+
+	add := func(name, cluster, port string, pod *v1.Pod) {
+		service, ok := byName[name]
+		if !ok {
+			icon := "📦"
+			if workload, ok := gatewayConfig.workload(name); ok && workload.Icon != "" {
+				icon = workload.Icon
+			}
+			service = &ServiceInfo{Name: name, Icon: icon, Deployments: map[string]string{}, Replicas: map[string]string{}, Port: port}
+			byName[name] = service
+			ready[name] = map[string][2]int{}
+		}
+		if service.Port == "" {
+			service.Port = port
+		}
+		// 대표 파드는 이름순 첫 번째 파드
+		if current, ok := service.Deployments[cluster]; !ok || pod.Name < current {
+			service.Deployments[cluster] = pod.Name
+		}
+		counts := ready[name][cluster]
+		counts[1]++
+		if podStatus(pod) == string(v1.PodRunning) {
+			counts[0]++
+		}
+		ready[name][cluster] = counts
+	}
+
+	for _, inventory := range inventories {
+		for i := range inventory.pods {
+			pod := &inventory.pods[i]
+			name := workloadName(pod)
+			add(name, podCluster(pod, inventory.info.Name), inventory.ports[name], pod)
+		}
+		for i := range inventory.gateways {
+			add("eastwest-gateway", inventory.info.Name, "15443", &inventory.gateways[i])
+		}
+	}
+
+	services := make([]ServiceInfo, 0, len(byName))
+	for name, service := range byName {
+		for cluster, counts := range ready[name] {
+			service.Replicas[cluster] = fmt.Sprintf("%d/%d", counts[0], counts[1])
+		}
+		services = append(services, *service)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
+
+// subsetClusters maps host -> subset -> cluster label from the DestinationRules
+func subsetClusters(ctx context.Context) (map[string]map[string]string, error) {
+	result := map[string]map[string]string{}
+	rules, err := istioClient.NetworkingV1().DestinationRules(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+	for _, rule := range rules.Items {
+		subsets := map[string]string{}
+		for _, subset := range rule.Spec.Subsets {
+			cluster := subset.Labels["cluster"]
+			if cluster == "" {
+				cluster = subset.Name
+			}
+			subsets[subset.Name] = cluster
+		}
+		result[shortHost(rule.Spec.Host)] = subsets
+	}
+	return result, nil
+}
+
+// shortHost turns "user-service.theater-msa.svc.cluster.local" into "user-service"
+func shortHost(host string) string {
+	name, _, _ := strings.Cut(host, ".")
+	return name
+}
+
+// buildTrafficFlows derives the gateway's outbound flows from the VirtualService weighted routes
+func buildTrafficFlows(ctx context.Context, services []ServiceInfo) ([]TrafficFlowInfo, error) {
+	local := localClusterName()
+	gateway := "api-gateway-" + local
+
+	present := map[string]bool{}
+	for _, service := range services {
+		for cluster := range service.Deployments {
+			present[service.Name+"-"+cluster] = true
+		}
+	}
+
+	flows := []TrafficFlowInfo{}
+	if present[gateway] {
+		flows = append(flows, TrafficFlowInfo{From: "external", To: gateway, Weight: 100, IsActive: true, FlowType: "external"})
+	}
+	if istioClient == nil {
+		return flows, errIstioUnavailable
+	}
+
+	subsets, err := subsetClusters(ctx)
+	if err != nil {
+		return flows, err
+	}
+	virtualServices, err := istioClient.NetworkingV1().VirtualServices(gatewayConfig.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return flows, err
+	}
+
+	crossCluster := map[string]int{} // 원격 클러스터 -> east-west gateway로 향하는 가중치 합
+	for _, vs := range virtualServices.Items {
+		route, err := weightedRoute(vs)
+		if err != nil || len(vs.Spec.Hosts) == 0 {
+			continue
+		}
+		host := shortHost(vs.Spec.Hosts[0])
+		if host == "api-gateway" {
+			continue // 외부 유입 경로
+		}
+
+		for _, destination := range route.Route {
+			if destination.Destination == nil {
+				continue
+			}
+			target := shortHost(destination.Destination.Host)
+			cluster := subsets[target][destination.Destination.Subset]
+			if cluster == "" {
+				cluster = destination.Destination.Subset
+			}
+			weight := int(destination.Weight)
+			if len(route.Route) == 1 && weight == 0 {
+				weight = 100
+			}
+
+			node := target + "-" + cluster
+			remoteGateway := "eastwest-gateway-" + cluster
+			switch {
+			case cluster == local:
+				flows = append(flows, TrafficFlowInfo{From: gateway, To: node, Weight: weight, IsActive: weight > 0, FlowType: "internal"})
+			case present[remoteGateway]:
+				crossCluster[cluster] += weight
+				flows = append(flows, TrafficFlowInfo{From: remoteGateway, To: node, Weight: weight, IsActive: weight > 0, FlowType: "internal"})
+			default:
+				flows = append(flows, TrafficFlowInfo{From: gateway, To: node, Weight: weight, IsActive: weight > 0, FlowType: "cross-cluster"})
+			}
+		}
+	}
+
+	// 사이드카는 원격 클러스터의 east-west gateway(15443)로 직접 연결
+	clusterNames := make([]string, 0, len(crossCluster))
+	for cluster := range crossCluster {
+		clusterNames = append(clusterNames, cluster)
+	}
+	sort.Strings(clusterNames)
+	for _, cluster := range clusterNames {
+		weight := crossCluster[cluster]
+		flows = append(flows, TrafficFlowInfo{From: gateway, To: "eastwest-gateway-" + cluster, Weight: weight, IsActive: weight > 0, FlowType: "cross-cluster"})
+	}
+	return flows, nil
+}
+
+// discoverTopology builds the topology from every reachable cluster and the Istio routing configuration
+func discoverTopology(ctx context.Context) MultiClusterTopology {
+	topology := MultiClusterTopology{
+		Clusters:    []ClusterInfo{},
+		Services:    []ServiceInfo{},
+		TrafficFlow: []TrafficFlowInfo{},
+		LastUpdated: time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	members, err := clusters.members(ctx)
+	if err != nil {
+		topology.Errors = append(topology.Errors, err.Error())
+	}
+
+	inventories := make([]clusterInventory, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, member memberCluster) {
+			defer wg.Done()
+			inventories[i] = discoverCluster(ctx, member)
+		}(i, member)
+	}
+	wg.Wait()
+
+	for _, inventory := range inventories {
+		topology.Clusters = append(topology.Clusters, inventory.info)
+		if inventory.info.Error != "" {
+			topology.Errors = append(topology.Errors, fmt.Sprintf("%s: %s", inventory.info.Name, inventory.info.Error))
+		}
+	}
+	topology.Services = buildServices(inventories)
+
+	flows, err := buildTrafficFlows(ctx, topology.Services)
+	topology.TrafficFlow = flows
+	if err != nil {
+		topology.Errors = append(topology.Errors, fmt.Sprintf("traffic flows: %v", err))
+	}
+	return topology
+}
+
+// getMultiClusterTopology returns the discovered multi-cluster topology; ?format=dot|mermaid renders a diagram
+func getMultiClusterTopology(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "dot" && format != "mermaid" {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid format: use json, dot or mermaid")
+		return
+	}
+
+	topology := discoverTopology(r.Context())
+	if len(topology.Errors) > 0 {
+		slog.WarnContext(r.Context(), "Topology discovery incomplete", "errors", topology.Errors)
+	}
+
+	switch format {
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		fmt.Fprint(w, renderTopologyDOT(topology))
+	case "mermaid":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, renderTopologyMermaid(topology))
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(topology)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// topologyNodes groups the service deployments by cluster
+func topologyNodes(topology MultiClusterTopology) map[string][]ServiceInfo {
+	byCluster := map[string][]ServiceInfo{}
+	for _, service := range topology.Services {
+		for cluster := range service.Deployments {
+			byCluster[cluster] = append(byCluster[cluster], service)
+		}
+	}
+	return byCluster
+}
+
+// clusterLabel describes a cluster in a subgraph title
+func clusterLabel(topology MultiClusterTopology, name string) string {
+	for _, cluster := range topology.Clusters {
+		if cluster.Name == name {
+			return fmt.Sprintf("%s (%s, %d nodes, %s)", cluster.Name, cluster.Provider, cluster.NodeCount, cluster.Status)
+		}
+	}
+	return name
+}
+
+// sortedClusterNames lists the clusters in topology order followed by clusters only seen on pods
+func sortedClusterNames(topology MultiClusterTopology, byCluster map[string][]ServiceInfo) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, cluster := range topology.Clusters {
+		names = append(names, cluster.Name)
+		seen[cluster.Name] = true
+	}
+	extra := []string{}
+	for name := range byCluster {
+		if !seen[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+func flowLabel(flow TrafficFlowInfo) string {
+	return fmt.Sprintf("%d%%", flow.Weight)
+}
+
+// renderTopologyDOT renders the topology as a Graphviz digraph
+func renderTopologyDOT(topology MultiClusterTopology) string {
+	var b strings.Builder
+	byCluster := topologyNodes(topology)
+
+	b.WriteString("digraph topology {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\"];\n")
+	b.WriteString("  \"external\" [shape=ellipse, label=\"external\"];\n")
+
+	for _, cluster := range sortedClusterNames(topology, byCluster) {
+		fmt.Fprintf(&b, "  subgraph %q {\n", "cluster_"+cluster)
+		fmt.Fprintf(&b, "    label=%q;\n", clusterLabel(topology, cluster))
+		for _, service := range byCluster[cluster] {
+			// %q 가 따옴표/역슬래시를 이스케이프하고 줄바꿈은 DOT 의 \n 으로 출력
+			label := fmt.Sprintf("%s %s\n%s", service.Icon, service.Name, service.Replicas[cluster])
+			fmt.Fprintf(&b, "    %q [label=%q];\n", service.Name+"-"+cluster, label)
+		}
+		b.WriteString("  }\n")
+	}
+
+	for _, flow := range topology.TrafficFlow {
+		style, color := "solid", "#4299e1"
+		switch flow.FlowType {
+		case "cross-cluster":
+			style, color = "dashed", "#ed8936"
+		case "external":
+			style, color = "bold", "#2d3748"
+		}
+		if !flow.IsActive {
+			style, color = "dotted", "#a0aec0"
+		}
+		attrs := []string{
+			fmt.Sprintf("label=%q", flowLabel(flow)),
+			"style=" + style,
+			fmt.Sprintf("color=%q", color),
+			fmt.Sprintf("fontcolor=%q", color),
+		}
+		fmt.Fprintf(&b, "  %q -> %q [%s];\n", flow.From, flow.To, strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidID turns a node name into a Mermaid-safe identifier
+func mermaidID(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name)
+}
+
+// mermaidLabel escapes a quoted Mermaid label; quotes would end the label early
+func mermaidLabel(label string) string {
+	return strings.NewReplacer(`"`, "#quot;").Replace(label)
+}
+
+// renderTopologyMermaid renders the topology as a Mermaid flowchart
+func renderTopologyMermaid(topology MultiClusterTopology) string {
+	var b strings.Builder
+	byCluster := topologyNodes(topology)
+
+	b.WriteString("flowchart LR\n")
+	b.WriteString("  external([external])\n")
+	for _, cluster := range sortedClusterNames(topology, byCluster) {
+		fmt.Fprintf(&b, "  subgraph %s[\"%s\"]\n", mermaidID("cluster_"+cluster), mermaidLabel(clusterLabel(topology, cluster)))
+		for _, service := range byCluster[cluster] {
+			label := mermaidLabel(fmt.Sprintf("%s %s", service.Icon, service.Name)) + "<br/>" + mermaidLabel(service.Replicas[cluster])
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", mermaidID(service.Name+"-"+cluster), label)
+		}
+		b.WriteString("  end\n")
+	}
+
+	var inactive []int
+	for i, flow := range topology.TrafficFlow {
+		arrow := "-->"
+		switch flow.FlowType {
+		case "cross-cluster":
+			arrow = "-.->"
+		case "external":
+			arrow = "==>"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", mermaidID(flow.From), arrow, flowLabel(flow), mermaidID(flow.To))
+		if !flow.IsActive {
+			inactive = append(inactive, i)
+		}
+	}
+
+	// 비활성 경로(가중치 0)는 회색 점선으로 표시
+	for _, index := range inactive {
+		fmt.Fprintf(&b, "  linkStyle %d stroke:#a0aec0,stroke-dasharray:3 3\n", index)
+	}
+	return b.String()
+}
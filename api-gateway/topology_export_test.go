@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func exportTopology(name, replicas string) MultiClusterTopology {
+	return MultiClusterTopology{
+		Clusters: []ClusterInfo{{Name: "ctx1", Provider: "kind", NodeCount: 1, Status: "Ready"}},
+		Services: []ServiceInfo{{
+			Name:        name,
+			Icon:        "🎬",
+			Deployments: map[string]string{"ctx1": name + "-ctx1-abc"},
+			Replicas:    map[string]string{"ctx1": replicas},
+		}},
+		TrafficFlow: []TrafficFlowInfo{
+			{From: "external", To: name + "-ctx1", Weight: 100, IsActive: true, FlowType: "external"},
+			{From: name + "-ctx1", To: name + "-ctx2", Weight: 0, IsActive: false, FlowType: "cross-cluster"},
+		},
+	}
+}
+
+func TestRenderTopologyDOT(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  string
+		replicas string
+		want     []string
+	}{
+		{
+			name:     "plain names",
+			service:  "movie-service",
+			replicas: "2/2",
+			want: []string{
+				`subgraph "cluster_ctx1" {`,
+				`label="ctx1 (kind, 1 nodes, Ready)";`,
+				`"movie-service-ctx1" [label="🎬 movie-service\n2/2"];`,
+				`"external" -> "movie-service-ctx1" [label="100%", style=bold, color="#2d3748", fontcolor="#2d3748"];`,
+				`"movie-service-ctx1" -> "movie-service-ctx2" [label="0%", style=dotted, color="#a0aec0", fontcolor="#a0aec0"];`,
+			},
+		},
+		{
+			name:     "quotes and backslashes are escaped",
+			service:  `say "hi"\now`,
+			replicas: `1/1`,
+			want: []string{
+				`"say \"hi\"\\now-ctx1" [label="🎬 say \"hi\"\\now\n1/1"];`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dot := renderTopologyDOT(exportTopology(tt.service, tt.replicas))
+			if !strings.HasPrefix(dot, "digraph topology {\n") || !strings.HasSuffix(dot, "}\n") {
+				t.Errorf("not a digraph:\n%s", dot)
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(dot, line) {
+					t.Errorf("missing %s in\n%s", line, dot)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTopologyMermaid(t *testing.T) {
+	mermaid := renderTopologyMermaid(exportTopology("movie-service", `2/2 "ready"`))
+	for _, line := range []string{
+		"flowchart LR\n",
+		`subgraph cluster_ctx1["ctx1 (kind, 1 nodes, Ready)"]`,
+		`movie_service_ctx1["🎬 movie-service<br/>2/2 #quot;ready#quot;"]`,
+		"external ==>|100%| movie_service_ctx1",
+		"movie_service_ctx1 -.->|0%| movie_service_ctx2",
+		"linkStyle 1 stroke:#a0aec0,stroke-dasharray:3 3",
+	} {
+		if !strings.Contains(mermaid, line) {
+			t.Errorf("missing %s in\n%s", line, mermaid)
+		}
+	}
+}
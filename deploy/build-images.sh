@@ -49,19 +49,28 @@ for SERVICE in "${SERVICES[@]}"; do
     else
         SERVICE_DIR="./services/${SERVICE}"
     fi
+
+    # pkg/platform, pkg/theaterpb 모듈을 함께 복사하도록 저장소 루트를 빌드 컨텍스트로 사용
+    BUILD_ARGS="-f ${SERVICE_DIR}/Dockerfile ."
     
+    # API Gateway 이미지에 장애 시나리오(practice/) 포함
+    if [ "${SERVICE}" = "api-gateway" ]; then
+        rm -rf "${SERVICE_DIR}/scenarios"
+        cp -r ./practice "${SERVICE_DIR}/scenarios"
+    fi
+
     # 컨테이너 런타임 자동 감지 및 빌드
     if [ -d "${SERVICE_DIR}" ]; then
         echo "  - 빌드: ${IMAGE_TAG_LATEST}"
         
         # 컨테이너 런타임 확인 (docker 또는 podman)
         if command -v docker >/dev/null 2>&1; then
-            docker build -t ${IMAGE_TAG_LATEST} ${SERVICE_DIR}/
+            docker build -t ${IMAGE_TAG_LATEST} ${BUILD_ARGS}
             echo "  - 푸시: ${IMAGE_TAG_LATEST}"
             docker push ${IMAGE_TAG_LATEST}
         elif command -v podman >/dev/null 2>&1; then
             # Podman에서 docker.io 레지스트리 명시적 사용
-            podman build --format docker -t ${IMAGE_TAG_LATEST} ${SERVICE_DIR}/
+            podman build --format docker -t ${IMAGE_TAG_LATEST} ${BUILD_ARGS}
             echo "  - 푸시: ${IMAGE_TAG_LATEST}"
             podman push ${IMAGE_TAG_LATEST}
         else
@@ -69,6 +78,10 @@ for SERVICE in "${SERVICES[@]}"; do
             exit 1
         fi
         
+        if [ "${SERVICE}" = "api-gateway" ]; then
+            rm -rf "${SERVICE_DIR}/scenarios"
+        fi
+
         echo "  ✓ 완료: ${SERVICE}"
     else
         echo "  ⚠ 경고: ${SERVICE_DIR} 디렉토리를 찾을 수 없습니다"
@@ -0,0 +1,34 @@
+package platform
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// AuthorizeBearer checks "Authorization: Bearer <token>" against token and answers 401 (or 503 when
+// token is empty, i.e. ADMIN_TOKEN is not set) itself; the gateway admin API and the fault API share it
+func AuthorizeBearer(w http.ResponseWriter, r *http.Request, token, realm string) bool {
+	if token == "" {
+		Error(w, r, http.StatusServiceUnavailable, CodeAdminDisabled, "Admin API disabled: ADMIN_TOKEN is not set")
+		return false
+	}
+
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+realm+`"`)
+		Error(w, r, http.StatusUnauthorized, CodeUnauthorized, "Unauthorized")
+		return false
+	}
+	return true
+}
+
+// withAdminAuth protects the fault injection admin API with the gateway's ADMIN_TOKEN
+func (s *Service) withAdminAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !AuthorizeBearer(w, r, s.Config.AdminToken, s.Name+"-admin") {
+			return
+		}
+		next(w, r)
+	}
+}
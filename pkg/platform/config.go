@@ -0,0 +1,133 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ServerConfig configures the HTTP server and graceful shutdown
+type ServerConfig struct {
+	Port              int
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration // 장애 주입 지연보다 길어야 함
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	DrainDelay        time.Duration // SIGTERM 후 readiness 실패 상태로 요청을 계속 받는 시간
+	ShutdownTimeout   time.Duration // 진행 중 요청 완료를 기다리는 최대 시간
+
+	GRPCPort             int           // 0 이면 gRPC 서버를 열지 않음
+	GRPCMaxConnectionAge time.Duration // 0 이면 제한 없음
+}
+
+// RedisConfig configures the Redis client
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+// Config is the environment-driven configuration shared by all services
+type Config struct {
+	Server         ServerConfig
+	Redis          RedisConfig
+	FaultInjection bool
+	AdminToken     string // 장애 주입 관리 API 의 Bearer 토큰, 게이트웨이와 같은 값
+	Validation     string // OpenAPI 검증: off, request, full(요청+응답)
+}
+
+// envDuration parses a Go duration ("30s", "1m") from the environment
+func envDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s: invalid duration %q", key, value)
+	}
+	return d, nil
+}
+
+// envInt parses a non-negative integer from the environment
+func envInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s: invalid number %q", key, value)
+	}
+	return n, nil
+}
+
+// envString returns the variable or the default when unset
+func envString(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// LoadConfig reads PORT, GRPC_*, HTTP_*, SHUTDOWN_*, REDIS_*, FAULT_INJECTION_ENABLED, ADMIN_TOKEN and OPENAPI_VALIDATION over the defaults
+func LoadConfig(defaultPort, defaultGRPCPort int) (Config, error) {
+	var (
+		cfg  Config
+		errs []error
+	)
+	duration := func(key string, defaultValue time.Duration) time.Duration {
+		d, err := envDuration(key, defaultValue)
+		errs = append(errs, err)
+		return d
+	}
+	number := func(key string, defaultValue int) int {
+		n, err := envInt(key, defaultValue)
+		errs = append(errs, err)
+		return n
+	}
+
+	cfg.Server = ServerConfig{
+		Port:              number("PORT", defaultPort),
+		ReadTimeout:       duration("HTTP_READ_TIMEOUT", 30*time.Second),
+		ReadHeaderTimeout: duration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		WriteTimeout:      duration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:       duration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		MaxHeaderBytes:    number("HTTP_MAX_HEADER_BYTES", 1<<20),
+		DrainDelay:        time.Duration(number("SHUTDOWN_DRAIN_SECONDS", 5)) * time.Second,
+		ShutdownTimeout:   duration("SHUTDOWN_TIMEOUT", 20*time.Second),
+
+		GRPCPort:             number("GRPC_PORT", defaultGRPCPort),
+		GRPCMaxConnectionAge: duration("GRPC_MAX_CONNECTION_AGE", 0),
+	}
+	cfg.Redis = RedisConfig{
+		Addr:     envString("REDIS_ADDR", "redis:6379"), // 멀티클러스터에서는 EastWestGateway 를 거쳐 ctx2 의 Redis 로 연결
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       number("REDIS_DB", 0),
+	}
+	cfg.FaultInjection = os.Getenv("FAULT_INJECTION_ENABLED") == "true"
+	cfg.AdminToken = os.Getenv("ADMIN_TOKEN")
+	cfg.Validation = envString("OPENAPI_VALIDATION", ValidationOff)
+
+	if err := errors.Join(errs...); err != nil {
+		return cfg, err
+	}
+	if cfg.Server.Port == 0 || cfg.Server.Port > 65535 {
+		return cfg, fmt.Errorf("PORT must be between 1 and 65535, got %d", cfg.Server.Port)
+	}
+	if cfg.Server.GRPCPort > 65535 || cfg.Server.GRPCPort == cfg.Server.Port {
+		return cfg, fmt.Errorf("GRPC_PORT must be between 0 and 65535 and differ from PORT, got %d", cfg.Server.GRPCPort)
+	}
+	switch cfg.Validation {
+	case ValidationOff, ValidationRequest, ValidationFull:
+	default:
+		return cfg, fmt.Errorf("OPENAPI_VALIDATION must be off, request or full, got %q", cfg.Validation)
+	}
+	if cfg.Server.ShutdownTimeout == 0 {
+		return cfg, fmt.Errorf("SHUTDOWN_TIMEOUT must be positive")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,356 @@
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FaultMatch selects the requests a fault rule applies to; empty fields match everything
+type FaultMatch struct {
+	Methods    []string          `json:"methods,omitempty"`
+	PathPrefix string            `json:"pathPrefix,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"` // exact match
+	Clusters   []string          `json:"clusters,omitempty"`
+}
+
+// FaultDelay mirrors Istio's fault.delay
+type FaultDelay struct {
+	Percentage float64 `json:"percentage"`
+	FixedDelay string  `json:"fixedDelay"` // "3s", "500ms"
+}
+
+// FaultAbort mirrors Istio's fault.abort
+type FaultAbort struct {
+	Percentage float64 `json:"percentage"`
+	HTTPStatus int     `json:"httpStatus"`
+}
+
+// FaultDrop closes the connection without sending a response
+type FaultDrop struct {
+	Percentage float64 `json:"percentage"`
+}
+
+// FaultRule is one fault injection rule; the first matching rule is applied, as in Istio
+type FaultRule struct {
+	ID    string      `json:"id"`
+	Match FaultMatch  `json:"match"`
+	Delay *FaultDelay `json:"delay,omitempty"`
+	Abort *FaultAbort `json:"abort,omitempty"`
+	Drop  *FaultDrop  `json:"drop,omitempty"`
+}
+
+// faultPresets reproduces the practice/ scenarios per service without Istio
+var faultPresets = map[string]map[string][]FaultRule{
+	"delay": {
+		"movie-service": {
+			{ID: "delay-fault", Delay: &FaultDelay{Percentage: 30, FixedDelay: "3s"}},
+		},
+	},
+	"error": {
+		"user-service": {
+			{ID: "circuit-test", Match: FaultMatch{Headers: map[string]string{"x-circuit-test": "true"}}, Abort: &FaultAbort{Percentage: 90, HTTPStatus: 500}},
+			{ID: "error-fault", Abort: &FaultAbort{Percentage: 30, HTTPStatus: 500}},
+		},
+	},
+	"block": {
+		"booking-service": {
+			{ID: "block-fault", Match: FaultMatch{Clusters: []string{"ctx2"}}, Abort: &FaultAbort{Percentage: 100, HTTPStatus: 503}},
+		},
+	},
+}
+
+func init() {
+	// 복합 장애는 개별 장애의 합
+	chaos := map[string][]FaultRule{}
+	for _, preset := range []string{"delay", "error", "block"} {
+		for service, rules := range faultPresets[preset] {
+			chaos[service] = append(chaos[service], rules...)
+		}
+	}
+	faultPresets["chaos"] = chaos
+}
+
+// faultStore holds the active rules, replaced atomically by the admin API
+type faultStore struct {
+	mu    sync.RWMutex
+	rules []FaultRule
+	seq   int
+}
+
+func (s *faultStore) list() []FaultRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]FaultRule{}, s.rules...)
+}
+
+func (s *faultStore) replace(rules []FaultRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = nil
+	for _, rule := range rules {
+		s.addLocked(rule)
+	}
+}
+
+func (s *faultStore) add(rule FaultRule) FaultRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addLocked(rule)
+}
+
+func (s *faultStore) addLocked(rule FaultRule) FaultRule {
+	if rule.ID == "" {
+		s.seq++
+		rule.ID = fmt.Sprintf("fault-%d", s.seq)
+	}
+	s.rules = append(s.rules, rule)
+	return rule
+}
+
+// put adds the rule or replaces the rule with the same ID in place; created is false on replace
+func (s *faultStore) put(rule FaultRule) (created bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.rules {
+		if s.rules[i].ID == rule.ID {
+			s.rules[i] = rule
+			return false
+		}
+	}
+	s.rules = append(s.rules, rule)
+	return true
+}
+
+func (s *faultStore) remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, rule := range s.rules {
+		if rule.ID == id {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the first rule matching the request served in cluster
+func (s *faultStore) match(r *http.Request, cluster string) (FaultRule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, rule := range s.rules {
+		if rule.Match.matches(r, cluster) {
+			return rule, true
+		}
+	}
+	return FaultRule{}, false
+}
+
+func (m FaultMatch) matches(r *http.Request, cluster string) bool {
+	if len(m.Methods) > 0 && !containsFold(m.Methods, r.Method) {
+		return false
+	}
+	if m.PathPrefix != "" && !strings.HasPrefix(r.URL.Path, m.PathPrefix) {
+		return false
+	}
+	for name, value := range m.Headers {
+		if r.Header.Get(name) != value {
+			return false
+		}
+	}
+	if len(m.Clusters) > 0 && !containsFold(m.Clusters, cluster) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r FaultRule) validate() error {
+	if r.Delay == nil && r.Abort == nil && r.Drop == nil {
+		return fmt.Errorf("rule needs at least one of delay, abort or drop")
+	}
+	if r.Delay != nil {
+		if _, err := time.ParseDuration(r.Delay.FixedDelay); err != nil {
+			return fmt.Errorf("invalid fixedDelay %q", r.Delay.FixedDelay)
+		}
+		if r.Delay.Percentage < 0 || r.Delay.Percentage > 100 {
+			return fmt.Errorf("delay percentage must be between 0 and 100")
+		}
+	}
+	if r.Abort != nil {
+		if r.Abort.HTTPStatus < 200 || r.Abort.HTTPStatus > 599 {
+			return fmt.Errorf("invalid abort httpStatus %d", r.Abort.HTTPStatus)
+		}
+		if r.Abort.Percentage < 0 || r.Abort.Percentage > 100 {
+			return fmt.Errorf("abort percentage must be between 0 and 100")
+		}
+	}
+	if r.Drop != nil && (r.Drop.Percentage < 0 || r.Drop.Percentage > 100) {
+		return fmt.Errorf("drop percentage must be between 0 and 100")
+	}
+	return nil
+}
+
+func hit(percentage float64) bool {
+	return rand.Float64()*100 < percentage
+}
+
+// withFaults applies the first matching fault rule before calling next
+func (s *Service) withFaults(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := s.faults.match(r, s.Cluster)
+		if !ok {
+			next(w, r)
+			return
+		}
+
+		if rule.Delay != nil && hit(rule.Delay.Percentage) {
+			delay, _ := time.ParseDuration(rule.Delay.FixedDelay)
+			slog.InfoContext(r.Context(), "Fault delay injected", "fault", rule.ID, "method", r.Method, "path", r.URL.Path, "delay", delay.String())
+			// 지연 구간을 별도 span 으로 남겨 Envoy span 과 비교할 수 있게 함
+			_, span := s.tracer.Start(r.Context(), "fault delay", trace.WithAttributes(
+				attribute.String("fault.id", rule.ID),
+				attribute.String("fault.delay", delay.String()),
+				attribute.String("cluster", s.Cluster),
+			))
+			select {
+			case <-time.After(delay):
+				span.End()
+			case <-r.Context().Done():
+				span.End()
+				return
+			}
+		}
+
+		if rule.Drop != nil && hit(rule.Drop.Percentage) {
+			slog.InfoContext(r.Context(), "Fault drop injected", "fault", rule.ID, "method", r.Method, "path", r.URL.Path)
+			trace.SpanFromContext(r.Context()).AddEvent("fault drop", trace.WithAttributes(attribute.String("fault.id", rule.ID)))
+			panic(http.ErrAbortHandler)
+		}
+
+		if rule.Abort != nil && hit(rule.Abort.Percentage) {
+			slog.InfoContext(r.Context(), "Fault abort injected", "fault", rule.ID, "method", r.Method, "path", r.URL.Path, "http_status", rule.Abort.HTTPStatus)
+			trace.SpanFromContext(r.Context()).AddEvent("fault abort", trace.WithAttributes(
+				attribute.String("fault.id", rule.ID),
+				attribute.Int("fault.http_status", rule.Abort.HTTPStatus),
+			))
+			w.Header().Set("X-Fault-Injected", rule.ID)
+			Error(w, r, rule.Abort.HTTPStatus, CodeFaultInjected, "fault filter abort")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// faultsAdminHandler manages fault rules at runtime:
+//
+//	GET    /admin/faults                 list rules
+//	POST   /admin/faults                 add a rule
+//	PUT    /admin/faults                 replace all rules
+//	PUT    /admin/faults/{id}            add or replace the rule with this ID (idempotent)
+//	DELETE /admin/faults[/{id}]          remove one or all rules
+//	POST   /admin/faults/presets/{name}  load a practice scenario (delay, error, block, chaos, reset)
+func (s *Service) faultsAdminHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	faults := s.faults
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/faults"), "/")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(faults.list())
+
+	case path == "" && r.Method == http.MethodPost:
+		var rule FaultRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			Error(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body")
+			return
+		}
+		if err := rule.validate(); err != nil {
+			Error(w, r, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(faults.add(rule))
+
+	case path == "" && r.Method == http.MethodPut:
+		var rules []FaultRule
+		if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+			Error(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body")
+			return
+		}
+		for _, rule := range rules {
+			if err := rule.validate(); err != nil {
+				Error(w, r, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+				return
+			}
+		}
+		faults.replace(rules)
+		json.NewEncoder(w).Encode(faults.list())
+
+	case path == "" && r.Method == http.MethodDelete:
+		faults.replace(nil)
+		w.WriteHeader(http.StatusNoContent)
+
+	case strings.HasPrefix(path, "presets/") && r.Method == http.MethodPost:
+		name := strings.TrimPrefix(path, "presets/")
+		if name == "reset" || name == "setup" {
+			faults.replace(nil)
+		} else if preset, ok := faultPresets[name]; ok {
+			faults.replace(preset[s.Name])
+		} else {
+			Error(w, r, http.StatusNotFound, CodeNotFound, "Unknown preset")
+			return
+		}
+		slog.InfoContext(r.Context(), "Fault preset applied", "preset", name, "rules", len(faults.list()))
+		json.NewEncoder(w).Encode(faults.list())
+
+	case path != "" && !strings.Contains(path, "/") && r.Method == http.MethodPut:
+		// 게이트웨이 chaos 실험이 파드마다 같은 ID 로 주입/삭제 (재시도해도 규칙이 중복되지 않음)
+		var rule FaultRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			Error(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body")
+			return
+		}
+		if rule.ID != "" && rule.ID != path {
+			Error(w, r, http.StatusBadRequest, CodeInvalidRequest, "Rule id does not match the path")
+			return
+		}
+		rule.ID = path
+		if err := rule.validate(); err != nil {
+			Error(w, r, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+			return
+		}
+		if faults.put(rule) {
+			w.WriteHeader(http.StatusCreated)
+		}
+		json.NewEncoder(w).Encode(rule)
+
+	case path != "" && r.Method == http.MethodDelete:
+		if !faults.remove(path) {
+			Error(w, r, http.StatusNotFound, CodeNotFound, "Fault rule not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		Error(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid fault operation")
+	}
+}
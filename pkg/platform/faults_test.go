@@ -0,0 +1,263 @@
+package platform
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func newFaultTestService(token string) *Service {
+	return &Service{
+		Telemetry: &Telemetry{Name: "user-service", Cluster: "ctx1", tracer: otel.Tracer("test")},
+		Config:    Config{FaultInjection: true, AdminToken: token},
+		faults:    &faultStore{},
+	}
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestWithFaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      []FaultRule
+		cluster    string
+		method     string
+		path       string
+		header     map[string]string
+		wantStatus int
+		wantFault  string
+	}{
+		{name: "no rules", method: http.MethodGet, path: "/users/1", wantStatus: http.StatusOK},
+		{
+			name:       "abort",
+			rules:      []FaultRule{{ID: "error-fault", Abort: &FaultAbort{Percentage: 100, HTTPStatus: 503}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusServiceUnavailable,
+			wantFault:  "error-fault",
+		},
+		{
+			name:       "zero percentage",
+			rules:      []FaultRule{{ID: "error-fault", Abort: &FaultAbort{Percentage: 0, HTTPStatus: 503}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "method mismatch",
+			rules:      []FaultRule{{ID: "post-only", Match: FaultMatch{Methods: []string{"POST"}}, Abort: &FaultAbort{Percentage: 100, HTTPStatus: 500}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "path prefix mismatch",
+			rules:      []FaultRule{{ID: "movies", Match: FaultMatch{PathPrefix: "/movies/"}, Abort: &FaultAbort{Percentage: 100, HTTPStatus: 500}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "header match",
+			rules:      []FaultRule{{ID: "circuit-test", Match: FaultMatch{Headers: map[string]string{"x-circuit-test": "true"}}, Abort: &FaultAbort{Percentage: 100, HTTPStatus: 500}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			header:     map[string]string{"X-Circuit-Test": "true"},
+			wantStatus: http.StatusInternalServerError,
+			wantFault:  "circuit-test",
+		},
+		{
+			name:       "other cluster",
+			rules:      []FaultRule{{ID: "block-fault", Match: FaultMatch{Clusters: []string{"ctx2"}}, Abort: &FaultAbort{Percentage: 100, HTTPStatus: 503}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "cluster is case insensitive",
+			rules:      []FaultRule{{ID: "block-fault", Match: FaultMatch{Clusters: []string{"CTX1"}}, Abort: &FaultAbort{Percentage: 100, HTTPStatus: 503}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusServiceUnavailable,
+			wantFault:  "block-fault",
+		},
+		{
+			name: "first matching rule wins",
+			rules: []FaultRule{
+				{ID: "first", Abort: &FaultAbort{Percentage: 100, HTTPStatus: 502}},
+				{ID: "second", Abort: &FaultAbort{Percentage: 100, HTTPStatus: 503}},
+			},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusBadGateway,
+			wantFault:  "first",
+		},
+		{
+			name:       "delay then pass",
+			rules:      []FaultRule{{ID: "delay-fault", Delay: &FaultDelay{Percentage: 100, FixedDelay: "1ms"}}},
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFaultTestService("secret")
+			s.faults.replace(tt.rules)
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			for name, value := range tt.header {
+				r.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			s.withFaults(okHandler)(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("X-Fault-Injected"); got != tt.wantFault {
+				t.Errorf("X-Fault-Injected = %q, want %q", got, tt.wantFault)
+			}
+			if tt.wantFault == "" {
+				return
+			}
+			var problem Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != CodeFaultInjected || problem.Status != tt.wantStatus {
+				t.Errorf("problem = %+v, want code %s status %d", problem, CodeFaultInjected, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestWithFaultsDrop(t *testing.T) {
+	s := newFaultTestService("secret")
+	s.faults.replace([]FaultRule{{ID: "drop", Drop: &FaultDrop{Percentage: 100}}})
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recover() = %v, want http.ErrAbortHandler", p)
+		}
+	}()
+	s.withFaults(okHandler)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+}
+
+func TestFaultRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    FaultRule
+		wantErr bool
+	}{
+		{name: "abort", rule: FaultRule{Abort: &FaultAbort{Percentage: 50, HTTPStatus: 503}}},
+		{name: "delay", rule: FaultRule{Delay: &FaultDelay{Percentage: 50, FixedDelay: "500ms"}}},
+		{name: "no action", rule: FaultRule{}, wantErr: true},
+		{name: "bad delay", rule: FaultRule{Delay: &FaultDelay{Percentage: 50, FixedDelay: "soon"}}, wantErr: true},
+		{name: "delay above 100", rule: FaultRule{Delay: &FaultDelay{Percentage: 150, FixedDelay: "1s"}}, wantErr: true},
+		{name: "bad status", rule: FaultRule{Abort: &FaultAbort{Percentage: 50, HTTPStatus: 99}}, wantErr: true},
+		{name: "negative drop", rule: FaultRule{Drop: &FaultDrop{Percentage: -1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFaultsAdminAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+		wantCode      string
+	}{
+		{name: "valid token", token: "secret", authorization: "Bearer secret", wantStatus: http.StatusOK},
+		{name: "missing header", token: "secret", wantStatus: http.StatusUnauthorized, wantCode: CodeUnauthorized},
+		{name: "wrong token", token: "secret", authorization: "Bearer other", wantStatus: http.StatusUnauthorized, wantCode: CodeUnauthorized},
+		{name: "no bearer prefix", token: "secret", authorization: "secret", wantStatus: http.StatusUnauthorized, wantCode: CodeUnauthorized},
+		{name: "basic scheme", token: "secret", authorization: "Basic secret", wantStatus: http.StatusUnauthorized, wantCode: CodeUnauthorized},
+		{name: "token not set", authorization: "Bearer ", wantStatus: http.StatusServiceUnavailable, wantCode: CodeAdminDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFaultTestService(tt.token)
+			r := httptest.NewRequest(http.MethodGet, "/admin/faults", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			s.withAdminAuth(s.faultsAdminHandler)(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var problem Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestFaultsAdminHandler(t *testing.T) {
+	s := newFaultTestService("secret")
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		s.withAdminAuth(s.faultsAdminHandler)(w, r)
+		return w
+	}
+
+	if w := do(http.MethodPost, "/admin/faults", `{"abort":{"percentage":150,"httpStatus":500}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid rule: status = %d, want 400", w.Code)
+	}
+	if w := do(http.MethodPut, "/admin/faults/my-rule", `{"abort":{"percentage":50,"httpStatus":503}}`); w.Code != http.StatusCreated {
+		t.Errorf("put new rule: status = %d, want 201", w.Code)
+	}
+	if w := do(http.MethodPut, "/admin/faults/my-rule", `{"abort":{"percentage":60,"httpStatus":503}}`); w.Code != http.StatusOK {
+		t.Errorf("replace rule: status = %d, want 200", w.Code)
+	}
+	if w := do(http.MethodPut, "/admin/faults/my-rule", `{"id":"other","abort":{"percentage":60,"httpStatus":503}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("mismatched id: status = %d, want 400", w.Code)
+	}
+	if rules := s.faults.list(); len(rules) != 1 || rules[0].Abort.Percentage != 60 {
+		t.Errorf("rules = %+v, want one rule at 60%%", rules)
+	}
+
+	if w := do(http.MethodPost, "/admin/faults/presets/error", ""); w.Code != http.StatusOK {
+		t.Errorf("preset: status = %d, want 200", w.Code)
+	}
+	if rules := s.faults.list(); len(rules) != len(faultPresets["error"]["user-service"]) {
+		t.Errorf("preset rules = %+v, want the user-service error preset", rules)
+	}
+	if w := do(http.MethodPost, "/admin/faults/presets/unknown", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown preset: status = %d, want 404", w.Code)
+	}
+
+	if w := do(http.MethodDelete, "/admin/faults/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("delete missing: status = %d, want 404", w.Code)
+	}
+	if w := do(http.MethodDelete, "/admin/faults", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete all: status = %d, want 204", w.Code)
+	}
+	if rules := s.faults.list(); len(rules) != 0 {
+		t.Errorf("rules after delete = %+v, want none", rules)
+	}
+}
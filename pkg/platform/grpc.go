@@ -0,0 +1,216 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain of every gRPC error; Reason carries the same code as the REST problem
+const errorDomain = "theater-msa"
+
+// metadataCarrier adapts gRPC metadata to the OpenTelemetry propagator (traceparent, baggage)
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// grpcMetrics are the RED metrics of the gRPC API, the counterpart of httpMetrics
+type grpcMetrics struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func newGRPCMetrics(service string) *grpcMetrics {
+	factory := promauto.With(prometheus.DefaultRegisterer)
+	constLabels := prometheus.Labels{"service": service}
+	return &grpcMetrics{
+		requestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "theater",
+			Name:        "grpc_requests_total",
+			Help:        "gRPC calls handled, by method, status code and serving cluster.",
+			ConstLabels: constLabels,
+		}, []string{"grpc_method", "grpc_code", "cluster"}),
+
+		requestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   "theater",
+			Name:        "grpc_request_duration_seconds",
+			Help:        "gRPC call latency.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"grpc_method", "grpc_code", "cluster"}),
+	}
+}
+
+// newGRPCServer builds the gRPC server with the health service and reflection (grpcurl) registered;
+// services register their APIs on s.GRPC before Run
+func (s *Service) newGRPCServer() *grpc.Server {
+	s.grpcMetrics = newGRPCMetrics(s.Name)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		// 메시 없이 실행하면 클라이언트가 연결 하나에 고정되므로 주기적으로 재연결시켜 파드 간 분산
+		grpc.KeepaliveParams(keepalive.ServerParameters{MaxConnectionAge: s.Config.Server.GRPCMaxConnectionAge}),
+	)
+	s.grpcHealth = health.NewServer()
+	healthpb.RegisterHealthServer(server, s.grpcHealth)
+	reflection.Register(server)
+	return server
+}
+
+// unaryInterceptor is the gRPC counterpart of the HTTP middleware chain: it continues the trace and
+// X-Request-Id sent by the caller, sets the routing headers, recovers panics and records metrics and the access log
+func (s *Service) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	requestID := metadataCarrier(md).Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+
+	grpcService, grpcMethod := splitFullMethod(info.FullMethod)
+	ctx, span := s.tracer.Start(ctx, info.FullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", grpcService),
+			attribute.String("rpc.method", grpcMethod),
+		),
+	)
+	defer span.End()
+
+	// 게이트웨이가 HTTP 응답 헤더와 같은 방식으로 라우팅 결과를 기록
+	grpc.SetHeader(ctx, metadata.Pairs(
+		"x-service-cluster", s.Cluster,
+		"x-pod-name", s.Pod,
+		"x-service-name", s.Name,
+		strings.ToLower(RequestIDHeader), requestID,
+	))
+
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			slog.ErrorContext(ctx, "Panic in gRPC handler", "grpc_method", info.FullMethod, "panic", p, "stack", string(debug.Stack()))
+			err = GRPCError(ctx, codes.Internal, CodeInternal, "Internal error")
+		}
+
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		if isServerError(code) {
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+		labels := prometheus.Labels{"grpc_method": info.FullMethod, "grpc_code": code.String(), "cluster": s.Cluster}
+		s.grpcMetrics.requestsTotal.With(labels).Inc()
+		s.grpcMetrics.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+
+		level := slog.LevelInfo
+		if isServerError(code) {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(ctx, level, "request completed",
+			slog.String("protocol", "grpc"),
+			slog.String("grpc_method", info.FullMethod),
+			slog.String("grpc_code", code.String()),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		)
+	}()
+	return handler(ctx, req)
+}
+
+// splitFullMethod turns /theater.v1.UserService/GetUser into theater.v1.UserService and GetUser
+func splitFullMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
+
+// isServerError reports the codes counted like a 5xx response
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// GRPCError is the gRPC counterpart of Error: the status carries an ErrorInfo whose Reason is the
+// same machine-readable code as the REST problem, with the service, cluster, pod and request ID
+func GRPCError(ctx context.Context, code codes.Code, reason, message string) error {
+	st := status.New(code, message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+		Metadata: map[string]string{
+			"service":   instance.name,
+			"cluster":   instance.cluster,
+			"pod":       PodName(),
+			"requestId": RequestID(ctx),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// serveGRPC listens on GRPC_PORT and reports the health service as serving
+func (s *Service) serveGRPC(serveErr chan<- error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.Config.Server.GRPCPort))
+	if err != nil {
+		serveErr <- fmt.Errorf("grpc: %w", err)
+		return
+	}
+	s.grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	serveErr <- s.GRPC.Serve(listener)
+}
+
+// stopGRPC waits for in-flight calls until ctx expires, then closes the remaining connections
+func (s *Service) stopGRPC(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GRPC.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Warn("In-flight gRPC calls did not finish before the shutdown deadline")
+		s.GRPC.Stop()
+	}
+}
@@ -0,0 +1,96 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// redisPingTimeout bounds the dependency check so a hung Redis fails the probe instead of blocking it
+const redisPingTimeout = time.Second
+
+// DependencyCheck is the result of one dependency probe
+type DependencyCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"` // ok, fail
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ProbeReport is the body of /healthz, /readyz and /startupz
+type ProbeReport struct {
+	Status  string            `json:"status"` // ok, starting, draining, unavailable
+	Service string            `json:"service"`
+	Cluster string            `json:"cluster"`
+	Pod     string            `json:"pod"`
+	Checks  []DependencyCheck `json:"checks,omitempty"`
+}
+
+func (s *Service) newProbeReport(status string) ProbeReport {
+	return ProbeReport{Status: status, Service: s.Name, Cluster: s.Cluster, Pod: s.Pod}
+}
+
+// checkRedis sends a PING with redisPingTimeout
+func (s *Service) checkRedis(ctx context.Context) DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, redisPingTimeout)
+	defer cancel()
+
+	check := DependencyCheck{Name: "redis", Status: "ok"}
+	start := time.Now()
+	err := s.Redis.Ping(ctx).Err()
+	check.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		check.Status = "fail"
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func writeProbe(w http.ResponseWriter, report ProbeReport, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// healthzHandler is the liveness probe: the process is serving, dependencies are not checked
+func (s *Service) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, s.newProbeReport("ok"), true)
+}
+
+// readyzHandler fails while draining or when Redis does not answer
+func (s *Service) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		writeProbe(w, s.newProbeReport("draining"), false)
+		return
+	}
+	report := s.newProbeReport("ok")
+	report.Checks = []DependencyCheck{s.checkRedis(r.Context())}
+	ok := report.Checks[0].Status == "ok"
+	if !ok {
+		report.Status = "unavailable"
+	} else {
+		s.started.Store(true)
+	}
+	writeProbe(w, report, ok)
+}
+
+// startupzHandler succeeds once Redis has answered; liveness and readiness probes wait for it
+func (s *Service) startupzHandler(w http.ResponseWriter, r *http.Request) {
+	if s.started.Load() {
+		writeProbe(w, s.newProbeReport("ok"), true)
+		return
+	}
+	report := s.newProbeReport("ok")
+	report.Checks = []DependencyCheck{s.checkRedis(r.Context())}
+	ok := report.Checks[0].Status == "ok"
+	if ok {
+		s.started.Store(true)
+	} else {
+		report.Status = "starting"
+	}
+	writeProbe(w, report, ok)
+}
@@ -0,0 +1,42 @@
+package platform
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ClusterName reports the cluster the pod runs in: CLUSTER_NAME, else ctx1/ctx2 found in the pod name
+func ClusterName() string {
+	// 환경변수에서 클러스터명 확인
+	if cluster := os.Getenv("CLUSTER_NAME"); cluster != "" {
+		return cluster
+	}
+
+	// 파드명에서 클러스터 정보 추출
+	hostname := os.Getenv("HOSTNAME")
+	if strings.Contains(hostname, "ctx1") {
+		return "ctx1"
+	} else if strings.Contains(hostname, "ctx2") {
+		return "ctx2"
+	}
+
+	// 기본값
+	return "unknown"
+}
+
+// PodName is the pod name Kubernetes sets as the hostname
+func PodName() string {
+	return os.Getenv("HOSTNAME")
+}
+
+// withServiceHeaders adds the routing headers the gateway records (X-Service-Cluster, X-Pod-Name, X-Service-Name);
+// they are set before the handler runs so injected aborts carry them too
+func (s *Service) withServiceHeaders(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Service-Cluster", s.Cluster)
+		w.Header().Set("X-Pod-Name", s.Pod)
+		w.Header().Set("X-Service-Name", s.Name)
+		next(w, r)
+	}
+}
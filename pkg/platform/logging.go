@@ -0,0 +1,124 @@
+package platform
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is generated by the gateway (or Envoy) and forwarded to every service
+const RequestIDHeader = "X-Request-Id"
+
+// requestIDKey stores the request ID in the request context
+type requestIDKey struct{}
+
+// RequestID returns the X-Request-Id of the request being handled
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds request_id, trace_id and span_id from the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()), slog.String("span_id", spanContext.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// parseLogLevel accepts debug, info, warn and error
+func parseLogLevel(value string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// InitLogging installs the default logger and names this process in problem bodies;
+// LOG_FORMAT=json|text, LOG_LEVEL=debug|info|warn|error
+func InitLogging(name, cluster string) {
+	instance.name, instance.cluster = name, cluster
+	options := &slog.HandlerOptions{Level: parseLogLevel(os.Getenv("LOG_LEVEL"))}
+	var handler slog.Handler = slog.NewJSONHandler(os.Stdout, options)
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "text") {
+		handler = slog.NewTextHandler(os.Stdout, options)
+	}
+	logger := slog.New(contextHandler{handler}).With(
+		slog.String("service", name),
+		slog.String("cluster", cluster),
+		slog.String("pod", PodName()),
+	)
+	// 남아 있는 log 패키지 출력도 같은 형식으로
+	slog.SetDefault(logger)
+}
+
+// withRequestLogging carries the X-Request-Id into the context and writes one access log line per request
+func (t *Telemetry) withRequestLogging(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		// 리버스 프록시가 그대로 업스트림에 전달하도록 요청 헤더에도 설정
+		r.Header.Set(RequestIDHeader, requestID)
+		w.Header().Set(RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+		r = r.WithContext(ctx)
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			p := recover()
+			status := "aborted"
+			level := slog.LevelError
+			if p == nil {
+				code := recorder.status
+				if code == 0 {
+					code = http.StatusOK
+				}
+				status = strconv.Itoa(code)
+				level = slog.LevelInfo
+				if t.quiet(r.URL.Path) {
+					level = slog.LevelDebug
+				}
+				if code >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+			}
+			slog.LogAttrs(ctx, level, "request completed",
+				slog.String("method", r.Method),
+				slog.String("route", t.route(r.URL.Path)),
+				slog.String("path", r.URL.Path),
+				slog.String("status", status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			)
+			if p != nil {
+				panic(p)
+			}
+		}()
+		next(recorder, r)
+	}
+}
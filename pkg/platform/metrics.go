@@ -0,0 +1,139 @@
+package platform
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// httpMetrics are the RED metrics used by the Grafana dashboards together with the Envoy metrics
+type httpMetrics struct {
+	requestsTotal        *prometheus.CounterVec
+	requestErrorsTotal   *prometheus.CounterVec
+	requestDuration      *prometheus.HistogramVec
+	redisCommandDuration *prometheus.HistogramVec
+}
+
+// newHTTPMetrics registers the metrics with the default registry, labelled with the service name
+func newHTTPMetrics(service string) *httpMetrics {
+	factory := promauto.With(prometheus.DefaultRegisterer)
+	constLabels := prometheus.Labels{"service": service}
+	return &httpMetrics{
+		requestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "theater",
+			Name:        "http_requests_total",
+			Help:        "HTTP requests handled, by route, method, status and serving cluster.",
+			ConstLabels: constLabels,
+		}, []string{"route", "method", "status", "cluster"}),
+
+		requestErrorsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "theater",
+			Name:        "http_request_errors_total",
+			Help:        "HTTP requests answered with a 5xx status or aborted.",
+			ConstLabels: constLabels,
+		}, []string{"route", "method", "status", "cluster"}),
+
+		requestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   "theater",
+			Name:        "http_request_duration_seconds",
+			Help:        "HTTP request latency, including injected faults; long-lived event streams are excluded.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"route", "method", "status", "cluster"}),
+
+		redisCommandDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   "theater",
+			Name:        "redis_command_duration_seconds",
+			Help:        "Redis command latency by command and result (ok, nil, error).",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.ExponentialBuckets(0.0005, 2, 14), // 0.5ms ~ 4s
+		}, []string{"command", "result", "cluster"}),
+	}
+}
+
+// statusRecorder captures the status code written by the handler; Flush and Hijack keep SSE and WebSocket working
+type statusRecorder struct {
+	http.ResponseWriter
+	status    int
+	streaming bool // 이벤트 스트림 또는 WebSocket: 지연 시간 대신 연결 유지 시간
+}
+
+// record keeps the first status sent; headers are final at that point
+func (r *statusRecorder) record(code int) {
+	if r.status == 0 {
+		r.status = code
+		r.streaming = strings.HasPrefix(r.Header().Get("Content-Type"), "text/event-stream")
+	}
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.record(code)
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.record(http.StatusOK)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Flush() {
+	r.record(http.StatusOK)
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijacking not supported")
+	}
+	r.status = http.StatusSwitchingProtocols
+	r.streaming = true
+	return hijacker.Hijack()
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// withMetrics records the RED metrics of every request; dropped connections are counted as "aborted"
+// and long-lived event streams are left out of the latency histogram
+func (t *Telemetry) withMetrics(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			p := recover()
+			status := "aborted"
+			if p == nil {
+				code := recorder.status
+				if code == 0 {
+					code = http.StatusOK
+				}
+				status = strconv.Itoa(code)
+			}
+
+			labels := prometheus.Labels{"route": t.route(r.URL.Path), "method": r.Method, "status": status, "cluster": t.Cluster}
+			t.metrics.requestsTotal.With(labels).Inc()
+			if !recorder.streaming {
+				t.metrics.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+			}
+			if p != nil || recorder.status >= http.StatusInternalServerError {
+				t.metrics.requestErrorsTotal.With(labels).Inc()
+			}
+
+			if p != nil {
+				panic(p)
+			}
+		}()
+		next(recorder, r)
+	}
+}
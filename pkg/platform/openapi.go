@@ -0,0 +1,166 @@
+package platform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// OPENAPI_VALIDATION values
+const (
+	ValidationOff     = "off"
+	ValidationRequest = "request" // 스펙과 다른 요청은 400
+	ValidationFull    = "full"    // 응답도 검증, 스펙과 다른 응답은 500 으로 교체
+)
+
+// Problem codes answered by the validation middleware
+const (
+	CodeSchemaViolation         = "SCHEMA_VIOLATION"
+	CodeResponseSchemaViolation = "RESPONSE_SCHEMA_VIOLATION"
+)
+
+// openAPI is the parsed spec of the service, served as JSON and used for validation
+type openAPI struct {
+	doc    *openapi3.T
+	json   []byte
+	router routers.Router
+}
+
+// loadOpenAPI parses and validates the YAML or JSON spec embedded by the service
+func loadOpenAPI(spec []byte) (*openAPI, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("openapi spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("openapi spec: %w", err)
+	}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi spec: %w", err)
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi spec: %w", err)
+	}
+	return &openAPI{doc: doc, json: encoded, router: router}, nil
+}
+
+// openAPIHandler serves the spec at /openapi.json; the gateway merges these into one document
+func (s *Service) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(s.openapi.json)
+}
+
+var validationOptions = &openapi3filter.Options{
+	// 스펙과 다른 상태 코드도 위반으로 처리 (오류는 default 응답으로 문서화)
+	IncludeResponseStatus: true,
+	AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+}
+
+// bufferedResponse holds the response until it has been validated
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// withValidation rejects requests, and in full mode responses, that do not match the spec.
+// Paths or methods the spec does not describe are passed to the handler unchanged.
+func (s *Service) withValidation(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := s.openapi.router.FindRoute(r)
+		if err != nil {
+			next(w, r)
+			return
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    validationOptions,
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			slog.InfoContext(r.Context(), "Request rejected by OpenAPI validation", "route", route.Path, "error", validationDetail(err))
+			Error(w, r, http.StatusBadRequest, CodeSchemaViolation, validationDetail(err))
+			return
+		}
+		if s.Config.Validation != ValidationFull {
+			next(w, r)
+			return
+		}
+
+		buffered := &bufferedResponse{header: w.Header()}
+		next(buffered, r)
+		if buffered.status == 0 {
+			buffered.status = http.StatusOK
+		}
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 buffered.status,
+			Header:                 buffered.header,
+			Body:                   io.NopCloser(bytes.NewReader(buffered.body.Bytes())),
+			Options:                validationOptions,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Response rejected by OpenAPI validation", "route", route.Path, "status", buffered.status, "error", validationDetail(err))
+			Error(w, r, http.StatusInternalServerError, CodeResponseSchemaViolation, validationDetail(err))
+			return
+		}
+		w.WriteHeader(buffered.status)
+		w.Write(buffered.body.Bytes())
+	}
+}
+
+// validationDetail turns a kin-openapi error into one line naming the offending field
+func validationDetail(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		field := strings.Join(schemaErr.JSONPointer(), "/")
+		prefix := "body"
+		var requestErr *openapi3filter.RequestError
+		if errors.As(err, &requestErr) && requestErr.Parameter != nil {
+			prefix = requestErr.Parameter.In + " parameter " + requestErr.Parameter.Name
+		}
+		if field != "" {
+			return fmt.Sprintf("%s /%s: %s", prefix, field, schemaErr.Reason)
+		}
+		return fmt.Sprintf("%s: %s", prefix, schemaErr.Reason)
+	}
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.Error()
+	}
+	var responseErr *openapi3filter.ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.Reason
+	}
+	return err.Error()
+}
@@ -0,0 +1,118 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newRedisClient creates the client with the tracing hook before the metrics hook
+func (s *Service) newRedisClient() *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     s.Config.Redis.Addr,
+		Password: s.Config.Redis.Password,
+		DB:       s.Config.Redis.DB,
+	})
+	client.AddHook(redisTracingHook{tracer: s.tracer})
+	client.AddHook(redisMetricsHook{metrics: s.metrics, cluster: s.Cluster})
+	return client
+}
+
+// redisTracingHook creates a client span per Redis command as a child of the request span
+type redisTracingHook struct {
+	tracer trace.Tracer
+}
+
+func (h redisTracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	// 프로브의 PING 처럼 요청 밖에서 실행되는 명령은 루트 트레이스를 만들지 않음
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+	ctx, _ = h.tracer.Start(ctx, "redis "+cmd.Name(), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, attribute.String("db.operation", cmd.Name())))
+	return ctx, nil
+}
+
+func (h redisTracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd.Err())
+	return nil
+}
+
+func (h redisTracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+	ctx, _ = h.tracer.Start(ctx, "redis pipeline", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, attribute.Int("db.redis.pipeline_length", len(cmds))))
+	return ctx, nil
+}
+
+func (h redisTracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	endRedisSpan(ctx, firstError(cmds))
+	return nil
+}
+
+func endRedisSpan(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func firstError(cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if cmd.Err() != nil {
+			return cmd.Err()
+		}
+	}
+	return nil
+}
+
+// redisStartKey stores the time a Redis command was issued
+type redisStartKey struct{}
+
+// redisMetricsHook observes the latency of every Redis command
+type redisMetricsHook struct {
+	metrics *httpMetrics
+	cluster string
+}
+
+func (h redisMetricsHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (h redisMetricsHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.observe(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+func (h redisMetricsHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (h redisMetricsHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	h.observe(ctx, "pipeline", firstError(cmds))
+	return nil
+}
+
+func (h redisMetricsHook) observe(ctx context.Context, command string, err error) {
+	start, ok := ctx.Value(redisStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	result := "ok"
+	if errors.Is(err, redis.Nil) {
+		result = "nil"
+	} else if err != nil {
+		result = "error"
+	}
+	h.metrics.redisCommandDuration.WithLabelValues(command, result, h.cluster).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,96 @@
+package platform
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProblemContentType is the media type of every error body (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Machine-readable error codes shared by the services; services add their own (USER_NOT_FOUND, MOVIE_NOT_FOUND, ...)
+const (
+	CodeInvalidRequest   = "INVALID_REQUEST"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	CodeNotFound         = "NOT_FOUND"
+	CodeStoreError       = "STORE_ERROR"
+	CodeFaultInjected    = "FAULT_INJECTED"
+	CodeInternal         = "INTERNAL_ERROR"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeAdminDisabled    = "ADMIN_DISABLED"
+)
+
+// Problem is an RFC 7807 problem details body. Code is what clients switch on;
+// service/cluster/pod/requestId/traceId locate the failing instance and its logs and trace.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	Service   string `json:"service,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Upstream  string `json:"upstream,omitempty"` // 게이트웨이가 라우팅 대상 서비스 대신 응답할 때 그 서비스
+	RequestID string `json:"requestId,omitempty"`
+	TraceID   string `json:"traceId,omitempty"`
+}
+
+// InvalidError is a request the shared service layer rejects before storing it;
+// REST answers it as 400 and gRPC as InvalidArgument, both with CodeInvalidRequest
+type InvalidError string
+
+func (e InvalidError) Error() string { return string(e) }
+
+// instance names this process in problem bodies and gRPC error details; set by InitLogging
+var instance struct{ name, cluster string }
+
+// ProblemType turns a code into the problem type URI, e.g. USER_NOT_FOUND -> urn:theater-msa:problem:user-not-found
+func ProblemType(code string) string {
+	return "urn:theater-msa:problem:" + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// NewProblem describes an error answered for r by this pod
+func NewProblem(r *http.Request, status int, code, detail string) Problem {
+	problem := Problem{
+		Type:      ProblemType(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		Service:   instance.name,
+		Cluster:   instance.cluster,
+		Pod:       PodName(),
+		RequestID: RequestID(r.Context()),
+	}
+	if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.IsValid() {
+		problem.TraceID = spanContext.TraceID().String()
+	}
+	return problem
+}
+
+// WriteJSON answers with status and v encoded as JSON
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteProblem answers with the problem as application/problem+json
+func WriteProblem(w http.ResponseWriter, problem Problem) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// Error replaces http.Error: it answers a problem+json body with a machine-readable code
+func Error(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	WriteProblem(w, NewProblem(r, status, code, detail))
+}
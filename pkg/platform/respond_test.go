@@ -0,0 +1,119 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestProblemType(t *testing.T) {
+	tests := map[string]string{
+		CodeInvalidRequest: "urn:theater-msa:problem:invalid-request",
+		"USER_NOT_FOUND":   "urn:theater-msa:problem:user-not-found",
+		CodeFaultInjected:  "urn:theater-msa:problem:fault-injected",
+	}
+	for code, want := range tests {
+		if got := ProblemType(code); got != want {
+			t.Errorf("ProblemType(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+// testRequestContext carries a request ID and a sampled span like a traced request
+func testRequestContext() (context.Context, trace.SpanContext) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	return trace.ContextWithSpanContext(ctx, spanContext), spanContext
+}
+
+func TestError(t *testing.T) {
+	ctx, spanContext := testRequestContext()
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Length", "2") // 핸들러가 미리 설정한 길이는 제거
+	Error(w, r, http.StatusNotFound, "USER_NOT_FOUND", "User not found")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ProblemContentType)
+	}
+	if w.Header().Get("X-Content-Type-Options") != "nosniff" || w.Header().Get("Content-Length") != "" {
+		t.Errorf("headers = %v, want nosniff and no stale Content-Length", w.Header())
+	}
+
+	var problem Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	want := Problem{
+		Type:      "urn:theater-msa:problem:user-not-found",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "User not found",
+		Instance:  "/users/42",
+		Code:      "USER_NOT_FOUND",
+		Service:   instance.name,
+		Cluster:   instance.cluster,
+		Pod:       PodName(),
+		RequestID: "req-1",
+		TraceID:   spanContext.TraceID().String(),
+	}
+	if problem != want {
+		t.Errorf("problem = %+v, want %+v", problem, want)
+	}
+}
+
+func TestErrorWithoutTrace(t *testing.T) {
+	w := httptest.NewRecorder()
+	Error(w, httptest.NewRequest(http.MethodPost, "/movies/", nil), http.StatusBadRequest, CodeInvalidRequest, "")
+
+	var problem map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	// 빈 필드는 생략, code/type/title/status 는 항상 포함
+	for _, field := range []string{"detail", "requestId", "traceId", "upstream"} {
+		if _, ok := problem[field]; ok {
+			t.Errorf("problem has %s = %v, want it omitted", field, problem[field])
+		}
+	}
+	if problem["code"] != CodeInvalidRequest || problem["title"] != "Bad Request" || problem["status"] != float64(400) {
+		t.Errorf("problem = %v", problem)
+	}
+}
+
+func TestGRPCError(t *testing.T) {
+	ctx, _ := testRequestContext()
+	err := GRPCError(ctx, codes.NotFound, "USER_NOT_FOUND", "User not found")
+
+	st := status.Convert(err)
+	if st.Code() != codes.NotFound || st.Message() != "User not found" {
+		t.Errorf("status = %v %q, want NotFound %q", st.Code(), st.Message(), "User not found")
+	}
+	var info *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = d
+		}
+	}
+	if info == nil {
+		t.Fatalf("details = %v, want an ErrorInfo", st.Details())
+	}
+	// REST problem 의 code 와 같은 값을 Reason 으로 전달
+	if info.Reason != "USER_NOT_FOUND" || info.Domain != errorDomain || info.Metadata["requestId"] != "req-1" {
+		t.Errorf("error info = %+v", info)
+	}
+}
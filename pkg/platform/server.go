@@ -0,0 +1,100 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ShutdownHooks lets the caller take part in Serve's lifecycle; every hook is optional
+type ShutdownHooks struct {
+	Start    func(serveErr chan<- error)   // 추가 리스너(gRPC) 시작, 오류를 보내면 Serve 가 종료
+	Drain    func()                        // SIGTERM 수신: readiness 실패로 전환
+	Stop     func(ctx context.Context)     // 드레인 후 HTTP 서버 종료와 동시에 실행, ctx 는 ShutdownTimeout
+	Cleanups []func(context.Context) error // 서버가 모두 멈춘 뒤 실행 (Redis, 트레이스 flush)
+}
+
+// Serve runs the HTTP server on cfg.Port until SIGTERM/SIGINT, then fails readiness for DrainDelay,
+// waits up to ShutdownTimeout for in-flight requests and runs the cleanups
+func Serve(cfg ServerConfig, handler http.Handler, hooks ShutdownHooks) error {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	if hooks.Start != nil {
+		go hooks.Start(serveErr)
+	}
+
+	select {
+	case err := <-serveErr:
+		return err
+	case sig := <-signals:
+		if hooks.Drain != nil {
+			hooks.Drain()
+		}
+		slog.Info("Shutting down: readiness is failing", "signal", sig.String(), "drain_delay", cfg.DrainDelay.String())
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		if hooks.Stop != nil {
+			hooks.Stop(ctx)
+		}
+		close(stopped)
+	}()
+	if err := server.Shutdown(ctx); err != nil {
+		// 기한 안에 끝나지 않은 요청은 강제로 종료
+		slog.Warn("In-flight requests did not finish before the shutdown deadline", "error", err)
+		server.Close()
+	}
+	<-stopped
+	// 강제 종료 후에도 트레이스 flush 와 Redis 종료가 실행되도록 별도 기한 사용
+	cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelCleanup()
+	for _, cleanup := range hooks.Cleanups {
+		if cleanupErr := cleanup(cleanupCtx); cleanupErr != nil {
+			slog.Warn("Cleanup failed during shutdown", "error", cleanupErr)
+		}
+	}
+	slog.Info("Server stopped")
+	return nil
+}
+
+// serve runs the HTTP and gRPC servers with Serve; gRPC health turns NOT_SERVING with readiness
+func (s *Service) serve(cleanups ...func(context.Context) error) error {
+	hooks := ShutdownHooks{
+		Drain: func() {
+			s.draining.Store(true)
+			s.grpcHealth.Shutdown()
+		},
+		Stop:     s.stopGRPC,
+		Cleanups: cleanups,
+	}
+	if s.Config.Server.GRPCPort != 0 {
+		hooks.Start = s.serveGRPC
+	}
+	return Serve(s.Config.Server, s.Mux, hooks)
+}
@@ -0,0 +1,126 @@
+// Package platform is the plumbing shared by the theater services: cluster identity,
+// configuration, Redis, structured logging, metrics, tracing, fault injection,
+// Kubernetes probes and the HTTP and gRPC servers with graceful shutdown.
+//
+// A service only provides its handler, its gRPC API and a route label function:
+//
+//	svc, err := platform.New(platform.Options{Name: "user-service", Port: 8081, GRPCPort: 9081, Route: metricsRoute, OpenAPI: openapiSpec})
+//	if err != nil { ... }
+//	rdb = svc.Redis
+//	svc.Handle(usersHandler)
+//	theaterpb.RegisterUserServiceServer(svc.GRPC, userServer{})
+//	err = svc.Run()
+package platform
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// Options describes a service
+type Options struct {
+	Name string // Kubernetes 서비스명 = 파드 app 레이블 (예: user-service)
+	Port int    // 기본 포트, PORT 환경변수가 우선
+	// GRPCPort is the default gRPC port, overridden by GRPC_PORT
+	GRPCPort int
+	Route    func(path string) string // 메트릭/로그/span 이름에 쓰는 낮은 카디널리티 라우트 레이블
+
+	// OpenAPI is the service's OpenAPI 3 spec (YAML or JSON), served at /openapi.json and used by OPENAPI_VALIDATION
+	OpenAPI []byte
+}
+
+// Service is one service process; New builds it once at startup
+type Service struct {
+	*Telemetry
+	Config Config
+
+	// Redis is shared by all handlers; pass the request context so commands join the request trace
+	Redis *redis.Client
+
+	// Mux serves the API and the standard endpoints; services may register extra paths on it
+	Mux *http.ServeMux
+
+	// GRPC serves the gRPC API on GRPC_PORT with the health service and reflection;
+	// services register their servers on it before Run
+	GRPC *grpc.Server
+
+	grpcMetrics *grpcMetrics
+	grpcHealth  *health.Server
+	faults      *faultStore
+	openapi     *openAPI
+	started     atomic.Bool // Redis 가 처음 응답한 뒤 true
+	draining    atomic.Bool // SIGTERM 후 true
+}
+
+// New loads the configuration and sets up logging, tracing, metrics and the Redis client.
+// It registers process-wide state (default logger, tracer provider, Prometheus collectors), so call it once.
+func New(opts Options) (*Service, error) {
+	s := &Service{
+		Telemetry: NewTelemetry(TelemetryOptions{Name: opts.Name, Route: opts.Route}),
+		Mux:       http.NewServeMux(),
+		faults:    &faultStore{},
+	}
+
+	cfg, err := LoadConfig(opts.Port, opts.GRPCPort)
+	if err != nil {
+		return nil, err
+	}
+	s.Config = cfg
+
+	if opts.OpenAPI != nil {
+		if s.openapi, err = loadOpenAPI(opts.OpenAPI); err != nil {
+			return nil, err
+		}
+	} else if cfg.Validation != ValidationOff {
+		return nil, fmt.Errorf("OPENAPI_VALIDATION=%s needs an OpenAPI spec", cfg.Validation)
+	}
+
+	s.GRPC = s.newGRPCServer()
+	s.Redis = s.newRedisClient()
+	return s, nil
+}
+
+// Handle serves the API handler on "/" behind the standard middleware, plus /metrics,
+// the probes, /openapi.json and, when enabled, the fault injection admin API
+func (s *Service) Handle(handler http.HandlerFunc) {
+	if s.openapi != nil {
+		s.Mux.HandleFunc("/openapi.json", s.openAPIHandler)
+		if s.Config.Validation != ValidationOff {
+			// 장애 주입 응답은 검증하지 않도록 안쪽에서 감쌈
+			handler = s.withValidation(handler)
+			slog.Info("OpenAPI validation enabled", "mode", s.Config.Validation)
+		}
+	}
+	if s.Config.FaultInjection {
+		// 메시 없이 실행할 때 Istio 장애 주입 대신 사용
+		handler = s.withFaults(handler)
+		s.Mux.HandleFunc("/admin/faults", s.withAdminAuth(s.faultsAdminHandler))
+		s.Mux.HandleFunc("/admin/faults/", s.withAdminAuth(s.faultsAdminHandler))
+		slog.Info("Fault injection enabled")
+	}
+	s.Mux.Handle("/metrics", promhttp.Handler())
+	// Kubernetes 프로브: 메트릭/트레이스/접근 로그 대상에서 제외
+	s.Mux.HandleFunc("/healthz", s.healthzHandler)
+	s.Mux.HandleFunc("/readyz", s.readyzHandler)
+	s.Mux.HandleFunc("/startupz", s.startupzHandler)
+	// 게이트웨이/Envoy 가 전달한 traceparent 와 X-Request-Id 를 이어받아 서버 span 과 접근 로그 생성
+	s.Mux.Handle("/", s.Middleware(s.withServiceHeaders(handler)))
+}
+
+// Run serves until SIGTERM and shuts down gracefully, closing Redis and flushing traces
+func (s *Service) Run() error {
+	slog.Info("Service started", "port", s.Config.Server.Port, "grpc_port", s.Config.Server.GRPCPort)
+	return s.serve(s.closeRedis, s.Telemetry.Shutdown)
+}
+
+func (s *Service) closeRedis(context.Context) error {
+	return s.Redis.Close()
+}
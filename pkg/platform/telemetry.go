@@ -0,0 +1,86 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TelemetryOptions describes the process whose requests are logged, measured and traced
+type TelemetryOptions struct {
+	Name      string
+	Cluster   string                   // 비어 있으면 ClusterName()
+	Namespace string                   // trace resource 의 k8s.namespace.name, 선택
+	Route     func(path string) string // 메트릭/로그/span 이름에 쓰는 낮은 카디널리티 라우트 레이블
+	// Quiet reports paths polled by the UI and probes: their access logs are kept at debug level and they get no span
+	Quiet func(path string) bool
+}
+
+// Telemetry is the structured logging, RED metrics and tracing shared by the services and the gateway
+type Telemetry struct {
+	Name    string
+	Cluster string
+	Pod     string
+
+	namespace      string
+	route          func(path string) string
+	quiet          func(path string) bool
+	metrics        *httpMetrics
+	tracer         trace.Tracer
+	tracerProvider *sdktrace.TracerProvider
+}
+
+// NewTelemetry installs the default logger (see InitLogging) and tracer provider and registers the HTTP metrics.
+// It registers process-wide state, so call it once.
+func NewTelemetry(opts TelemetryOptions) *Telemetry {
+	t := &Telemetry{
+		Name:      opts.Name,
+		Cluster:   opts.Cluster,
+		Pod:       PodName(),
+		namespace: opts.Namespace,
+		route:     opts.Route,
+		quiet:     opts.Quiet,
+	}
+	if t.Cluster == "" {
+		t.Cluster = ClusterName()
+	}
+	if t.route == nil {
+		t.route = func(string) string { return "/" }
+	}
+	if t.quiet == nil {
+		t.quiet = func(string) bool { return false }
+	}
+	InitLogging(t.Name, t.Cluster)
+	t.initTracing()
+	t.metrics = newHTTPMetrics(t.Name)
+	return t
+}
+
+// Tracer creates the spans of this process; it is a no-op while tracing is disabled
+func (t *Telemetry) Tracer() trace.Tracer {
+	return t.tracer
+}
+
+// Middleware continues an incoming traceparent and X-Request-Id, starts the server span
+// and writes the access log and RED metrics of every request
+func (t *Telemetry) Middleware(handler http.HandlerFunc) http.Handler {
+	return otelhttp.NewHandler(t.withRequestLogging(t.withMetrics(handler)), t.Name,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + t.route(r.URL.Path)
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return !t.quiet(r.URL.Path)
+		}),
+	)
+}
+
+// Shutdown flushes the spans still queued in the batch processor
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	if t.tracerProvider == nil {
+		return nil
+	}
+	return t.tracerProvider.Shutdown(ctx)
+}
@@ -0,0 +1,91 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// tracesExporter reads OTEL_TRACES_EXPORTER (otlp, stdout, file, none); an OTLP endpoint alone enables otlp
+func tracesExporter() string {
+	if exporter := os.Getenv("OTEL_TRACES_EXPORTER"); exporter != "" {
+		return exporter
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		return "otlp"
+	}
+	return "none"
+}
+
+// initTracing installs the W3C trace-context propagator and, unless disabled, the tracer provider
+func (t *Telemetry) initTracing() {
+	// 트레이싱이 꺼져 있어도 traceparent 는 그대로 전달
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	// 전역 프로바이더를 따르므로 트레이싱이 꺼져 있으면 no-op
+	t.tracer = otel.Tracer("msa-sample-01/" + t.Name)
+
+	exporterName := tracesExporter()
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch exporterName {
+	case "none":
+		return
+	case "otlp":
+		// OTEL_EXPORTER_OTLP_ENDPOINT 등 표준 환경변수로 설정
+		exporter, err = otlptracehttp.New(context.Background())
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "file":
+		var file io.Writer
+		path := envString("OTEL_TRACES_FILE", t.Name+"-traces.jsonl")
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err == nil {
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		}
+	default:
+		err = errors.New("unknown exporter " + exporterName)
+	}
+	if err != nil {
+		slog.Warn("Tracing disabled", "error", err)
+		return
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.ServiceName(t.Name),
+		semconv.K8SPodName(t.Pod),
+		attribute.String("cluster", t.Cluster),
+	}
+	if t.namespace != "" {
+		attributes = append(attributes, semconv.K8SNamespaceName(t.namespace))
+	}
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(attributes...),
+		resource.WithFromEnv(), // OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES 우선
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		slog.Warn("Incomplete trace resource", "error", err)
+	}
+
+	// 오프라인 익스포터는 종료 시 유실되지 않도록 동기 처리
+	processor := sdktrace.NewBatchSpanProcessor(exporter)
+	if exporterName != "otlp" {
+		processor = sdktrace.NewSimpleSpanProcessor(exporter)
+	}
+	t.tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor), sdktrace.WithResource(res))
+	otel.SetTracerProvider(t.tracerProvider)
+	slog.Info("Tracing enabled", "exporter", exporterName)
+}
@@ -0,0 +1,10 @@
+// Package theaterpb holds the gRPC APIs of the theater services, generated from user.proto,
+// movie.proto and booking.proto. The services serve them next to REST (GRPC_PORT) and the
+// gateway uses the clients when GRPC_TRANSCODING is on.
+//
+// Regenerate after editing a .proto (protoc-gen-go v1.33.0, protoc-gen-go-grpc v1.3.0):
+//
+//	go generate ./...
+package theaterpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative user.proto movie.proto booking.proto
@@ -0,0 +1,189 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: movie.proto
+
+package theaterpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	MovieService_ListMovies_FullMethodName  = "/theater.v1.MovieService/ListMovies"
+	MovieService_GetMovie_FullMethodName    = "/theater.v1.MovieService/GetMovie"
+	MovieService_CreateMovie_FullMethodName = "/theater.v1.MovieService/CreateMovie"
+)
+
+// MovieServiceClient is the client API for MovieService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MovieServiceClient interface {
+	// ListMovies returns every movie, or only the given ids (GET /movies/?ids=a,b).
+	ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error)
+	// GetMovie returns one movie (GET /movies/{id}); NOT_FOUND with reason MOVIE_NOT_FOUND if unknown.
+	GetMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*Movie, error)
+	// CreateMovie stores a movie with a generated ID (POST /movies/).
+	CreateMovie(ctx context.Context, in *CreateMovieRequest, opts ...grpc.CallOption) (*Movie, error)
+}
+
+type movieServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMovieServiceClient(cc grpc.ClientConnInterface) MovieServiceClient {
+	return &movieServiceClient{cc}
+}
+
+func (c *movieServiceClient) ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error) {
+	out := new(ListMoviesResponse)
+	err := c.cc.Invoke(ctx, MovieService_ListMovies_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) GetMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_GetMovie_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) CreateMovie(ctx context.Context, in *CreateMovieRequest, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_CreateMovie_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MovieServiceServer is the server API for MovieService service.
+// All implementations must embed UnimplementedMovieServiceServer
+// for forward compatibility
+type MovieServiceServer interface {
+	// ListMovies returns every movie, or only the given ids (GET /movies/?ids=a,b).
+	ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error)
+	// GetMovie returns one movie (GET /movies/{id}); NOT_FOUND with reason MOVIE_NOT_FOUND if unknown.
+	GetMovie(context.Context, *GetMovieRequest) (*Movie, error)
+	// CreateMovie stores a movie with a generated ID (POST /movies/).
+	CreateMovie(context.Context, *CreateMovieRequest) (*Movie, error)
+	mustEmbedUnimplementedMovieServiceServer()
+}
+
+// UnimplementedMovieServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMovieServiceServer struct {
+}
+
+func (UnimplementedMovieServiceServer) ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) GetMovie(context.Context, *GetMovieRequest) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) CreateMovie(context.Context, *CreateMovieRequest) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) mustEmbedUnimplementedMovieServiceServer() {}
+
+// UnsafeMovieServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MovieServiceServer will
+// result in compilation errors.
+type UnsafeMovieServiceServer interface {
+	mustEmbedUnimplementedMovieServiceServer()
+}
+
+func RegisterMovieServiceServer(s grpc.ServiceRegistrar, srv MovieServiceServer) {
+	s.RegisterService(&MovieService_ServiceDesc, srv)
+}
+
+func _MovieService_ListMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).ListMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_ListMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).ListMovies(ctx, req.(*ListMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_GetMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).GetMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_GetMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).GetMovie(ctx, req.(*GetMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_CreateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).CreateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_CreateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).CreateMovie(ctx, req.(*CreateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MovieService_ServiceDesc is the grpc.ServiceDesc for MovieService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MovieService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "theater.v1.MovieService",
+	HandlerType: (*MovieServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListMovies",
+			Handler:    _MovieService_ListMovies_Handler,
+		},
+		{
+			MethodName: "GetMovie",
+			Handler:    _MovieService_GetMovie_Handler,
+		},
+		{
+			MethodName: "CreateMovie",
+			Handler:    _MovieService_CreateMovie_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "movie.proto",
+}
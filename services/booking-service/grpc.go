@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
+)
+
+// bookingServer serves theaterpb.BookingService from the same store as the REST handlers
+type bookingServer struct {
+	theaterpb.UnimplementedBookingServiceServer
+}
+
+func (b Booking) proto() *theaterpb.Booking {
+	return &theaterpb.Booking{Id: b.ID, UserId: b.UserID, MovieId: b.MovieID, Seats: b.Seats}
+}
+
+func bookingsResponse(bookings []Booking) *theaterpb.ListBookingsResponse {
+	resp := &theaterpb.ListBookingsResponse{Bookings: make([]*theaterpb.Booking, 0, len(bookings))}
+	for _, booking := range bookings {
+		resp.Bookings = append(resp.Bookings, booking.proto())
+	}
+	return resp
+}
+
+func (bookingServer) ListBookings(ctx context.Context, _ *theaterpb.ListBookingsRequest) (*theaterpb.ListBookingsResponse, error) {
+	bookings, err := findAllBookings(ctx)
+	if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to retrieve bookings")
+	}
+	return bookingsResponse(bookings), nil
+}
+
+func (bookingServer) ListUserBookings(ctx context.Context, req *theaterpb.ListUserBookingsRequest) (*theaterpb.ListBookingsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, platform.GRPCError(ctx, codes.InvalidArgument, platform.CodeInvalidRequest, "Invalid user ID")
+	}
+	bookings, err := findUserBookings(ctx, req.GetUserId())
+	if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to retrieve bookings")
+	}
+	return bookingsResponse(bookings), nil
+}
+
+func (bookingServer) CreateBooking(ctx context.Context, req *theaterpb.CreateBookingRequest) (*theaterpb.Booking, error) {
+	booking := Booking{UserID: req.GetUserId(), MovieID: req.GetMovieId(), Seats: req.GetSeats()}
+	var invalid platform.InvalidError
+	if err := storeBooking(ctx, &booking); errors.As(err, &invalid) {
+		return nil, platform.GRPCError(ctx, codes.InvalidArgument, platform.CodeInvalidRequest, invalid.Error())
+	} else if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to save booking")
+	}
+	return booking.proto(), nil
+}
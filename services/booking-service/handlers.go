@@ -1,35 +1,55 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"msa-sample-01/pkg/platform"
+)
+
+// 예약 비즈니스 메트릭
+var (
+	bookingsCreatedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "theater",
+		Name:      "bookings_created_total",
+		Help:      "Bookings stored successfully.",
+	}, []string{"cluster"})
+
+	seatsReservedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "theater",
+		Name:      "seats_reserved_total",
+		Help:      "Seats reserved by successful bookings.",
+	}, []string{"cluster"})
+
+	bookingsFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "theater",
+		Name:      "bookings_failed_total",
+		Help:      "Booking requests that did not create a booking, by reason.",
+	}, []string{"reason", "cluster"})
 )
 
 func bookingsHandler(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/")
 	// Remove "bookings/" prefix if present (from API Gateway routing)
 	path = strings.TrimPrefix(path, "bookings/")
-	w.Header().Set("Content-Type", "application/json")
-	
-	// 실제 라우팅 정보를 헤더에 추가
-	w.Header().Set("X-Service-Cluster", getClusterName())
-	w.Header().Set("X-Pod-Name", os.Getenv("HOSTNAME"))
-	w.Header().Set("X-Service-Name", "booking-service")
-	
+
 	// Debug logging
-	log.Printf("Request: %s %s, processed path: '%s'", r.Method, r.URL.Path, path)
+	slog.DebugContext(r.Context(), "Request", "method", r.Method, "path", r.URL.Path, "processed_path", path)
 
 	switch r.Method {
 	case http.MethodPost:
 		if path == "" {
 			createBookingHandler(w, r)
 		} else {
-			http.Error(w, "Invalid path for POST", http.StatusBadRequest)
+			platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid path for POST")
 		}
 	case http.MethodGet:
 		if path == "" {
@@ -39,71 +59,82 @@ func bookingsHandler(w http.ResponseWriter, r *http.Request) {
 			if userID != "" {
 				getUserBookingsHandler(w, r, userID)
 			} else {
-				http.Error(w, "Invalid user ID", http.StatusBadRequest)
+				platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid user ID")
 			}
 		} else {
-			http.Error(w, "Invalid path for GET", http.StatusBadRequest)
+			platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid path for GET")
 		}
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
+// metricsRoute maps a request path to a low-cardinality route label
+func metricsRoute(path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "/"), "bookings/")
+	switch {
+	case path == "":
+		return "/bookings/"
+	case strings.HasPrefix(path, "user/"):
+		return "/bookings/user/{userId}"
+	}
+	return "/bookings/{other}"
+}
+
 func createBookingHandler(w http.ResponseWriter, r *http.Request) {
 	var booking Booking
 	if err := json.NewDecoder(r.Body).Decode(&booking); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		bookingsFailedTotal.WithLabelValues("invalid_body", platform.ClusterName()).Inc()
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
+	var invalid platform.InvalidError
+	if err := storeBooking(r.Context(), &booking); errors.As(err, &invalid) {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, invalid.Error())
+		return
+	} else if err != nil {
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to save booking")
+		return
+	}
+
+	platform.WriteJSON(w, http.StatusCreated, booking)
+}
+
+// storeBooking validates the booking, assigns the ID, saves it and records the booking metrics; shared by REST and gRPC
+func storeBooking(ctx context.Context, booking *Booking) error {
+	if err := booking.validate(); err != nil {
+		bookingsFailedTotal.WithLabelValues("invalid_body", platform.ClusterName()).Inc()
+		return err
+	}
 	booking.ID = uuid.New().String()
 
-	if err := saveBooking(booking); err != nil {
-		http.Error(w, "Failed to save booking", http.StatusInternalServerError)
-		return
+	if err := saveBooking(ctx, *booking); err != nil {
+		bookingsFailedTotal.WithLabelValues("store_error", platform.ClusterName()).Inc()
+		return err
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(booking)
+	bookingsCreatedTotal.WithLabelValues(platform.ClusterName()).Inc()
+	seatsReservedTotal.WithLabelValues(platform.ClusterName()).Add(float64(len(booking.Seats)))
+	return nil
 }
 
 func getAllBookingsHandler(w http.ResponseWriter, r *http.Request) {
-	bookings, err := findAllBookings()
+	bookings, err := findAllBookings(r.Context())
 	if err != nil {
-		http.Error(w, "Failed to retrieve bookings", http.StatusInternalServerError)
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to retrieve bookings")
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(bookings)
+	platform.WriteJSON(w, http.StatusOK, bookings)
 }
 
 func getUserBookingsHandler(w http.ResponseWriter, r *http.Request, userID string) {
-	bookings, err := findUserBookings(userID)
+	bookings, err := findUserBookings(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "Failed to retrieve bookings", http.StatusInternalServerError)
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to retrieve bookings")
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(bookings)
-}
-
-// getClusterName은 현재 파드가 실행 중인 클러스터를 판단합니다
-func getClusterName() string {
-	// 환경변수에서 클러스터명 확인
-	if cluster := os.Getenv("CLUSTER_NAME"); cluster != "" {
-		return cluster
-	}
-	
-	// 파드명에서 클러스터 정보 추출
-	hostname := os.Getenv("HOSTNAME")
-	if strings.Contains(hostname, "ctx1") {
-		return "ctx1"
-	} else if strings.Contains(hostname, "ctx2") {
-		return "ctx2"
-	}
-	
-	// 기본값
-	return "unknown"
+	platform.WriteJSON(w, http.StatusOK, bookings)
 }
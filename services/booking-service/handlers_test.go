@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
+)
+
+// useUnreachableRedis points the store at a closed port so valid requests fail at the store, not at validation
+func useUnreachableRedis(t *testing.T) {
+	t.Helper()
+	previous := rdb
+	rdb = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() {
+		rdb.Close()
+		rdb = previous
+	})
+}
+
+func TestCreateBookingValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		booking    Booking
+		wantStatus int
+		wantCode   string
+		wantGRPC   codes.Code
+	}{
+		{name: "valid", booking: Booking{UserID: "u1", MovieID: "m1", Seats: []string{"A1"}}, wantStatus: http.StatusInternalServerError, wantCode: platform.CodeStoreError, wantGRPC: codes.Internal},
+		{name: "missing user", booking: Booking{MovieID: "m1", Seats: []string{"A1"}}, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+		{name: "missing movie", booking: Booking{UserID: "u1", Seats: []string{"A1"}}, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+		{name: "no seats", booking: Booking{UserID: "u1", MovieID: "m1"}, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useUnreachableRedis(t)
+			invalidBefore := testutil.ToFloat64(bookingsFailedTotal.WithLabelValues("invalid_body", platform.ClusterName()))
+
+			body, _ := json.Marshal(tt.booking)
+			w := httptest.NewRecorder()
+			bookingsHandler(w, httptest.NewRequest(http.MethodPost, "/bookings/", strings.NewReader(string(body))))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("REST status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var problem platform.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("REST code = %q, want %q", problem.Code, tt.wantCode)
+			}
+
+			// gRPC 도 같은 검증 결과
+			_, err := bookingServer{}.CreateBooking(context.Background(), &theaterpb.CreateBookingRequest{UserId: tt.booking.UserID, MovieId: tt.booking.MovieID, Seats: tt.booking.Seats})
+			if got := status.Code(err); got != tt.wantGRPC {
+				t.Errorf("gRPC code = %v, want %v", got, tt.wantGRPC)
+			}
+
+			// 거부된 요청은 REST, gRPC 모두 invalid_body 로 집계
+			wantInvalid := 0.0
+			if tt.wantGRPC == codes.InvalidArgument {
+				wantInvalid = 2
+			}
+			if got := testutil.ToFloat64(bookingsFailedTotal.WithLabelValues("invalid_body", platform.ClusterName())) - invalidBefore; got != wantInvalid {
+				t.Errorf("invalid_body failures = %v, want %v", got, wantInvalid)
+			}
+		})
+	}
+}
+
+func TestBookingsHandlerInvalidBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	bookingsHandler(w, httptest.NewRequest(http.MethodPost, "/bookings/", strings.NewReader(`{"userId":`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	bookingsHandler(w, httptest.NewRequest(http.MethodDelete, "/bookings/1", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE status = %d, want 405", w.Code)
+	}
+}
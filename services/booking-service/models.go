@@ -1,5 +1,7 @@
 package main
 
+import "msa-sample-01/pkg/platform"
+
 // Booking represents a booking model
 type Booking struct {
 	ID      string   `json:"id"`
@@ -7,3 +9,11 @@ type Booking struct {
 	MovieID string   `json:"movieId"`
 	Seats   []string `json:"seats"`
 }
+
+// validate checks the fields openapi.yaml marks as required, for REST and gRPC alike
+func (b Booking) validate() error {
+	if b.UserID == "" || b.MovieID == "" || len(b.Seats) == 0 {
+		return platform.InvalidError("userId, movieId and seats are required")
+	}
+	return nil
+}
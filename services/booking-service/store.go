@@ -3,26 +3,15 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 
 	"github.com/go-redis/redis/v8"
 )
 
-var (
-	rdb *redis.Client
-	ctx = context.Background()
-)
-
-func init() {
-	// Initialize Redis client
-	rdb = redis.NewClient(&redis.Options{
-		Addr:     "redis:6379", // Redis service from docker-compose
-		Password: "",
-		DB:       0, // Default DB
-	})
-}
+// rdb is the platform Redis client, set in main; every call takes the request context so Redis spans join the request trace
+var rdb *redis.Client
 
-func saveBooking(booking Booking) error {
+func saveBooking(ctx context.Context, booking Booking) error {
 	bookingJSON, err := json.Marshal(booking)
 	if err != nil {
 		return err
@@ -30,22 +19,22 @@ func saveBooking(booking Booking) error {
 
 	// Store the booking itself
 	if err := rdb.Set(ctx, "booking:"+booking.ID, bookingJSON, 0).Err(); err != nil {
-		log.Printf("Failed to save booking: %v", err)
+		slog.ErrorContext(ctx, "Failed to save booking", "error", err)
 		return err
 	}
 
 	// Add the booking ID to a list for the user
 	if err := rdb.LPush(ctx, "user_bookings:"+booking.UserID, booking.ID).Err(); err != nil {
-		log.Printf("Failed to update user's booking list: %v", err)
+		slog.ErrorContext(ctx, "Failed to update user's booking list", "error", err)
 		// This is not a fatal error for the booking creation itself, but should be logged.
 	}
 	return nil
 }
 
-func findUserBookings(userID string) ([]Booking, error) {
+func findUserBookings(ctx context.Context, userID string) ([]Booking, error) {
 	bookingIDs, err := rdb.LRange(ctx, "user_bookings:"+userID, 0, -1).Result()
 	if err != nil {
-		log.Printf("Failed to get booking IDs for user %s: %v", userID, err)
+		slog.ErrorContext(ctx, "Failed to get booking IDs", "user_id", userID, "error", err)
 		return nil, err
 	}
 
@@ -61,7 +50,7 @@ func findUserBookings(userID string) ([]Booking, error) {
 
 	bookingsData, err := rdb.MGet(ctx, keys...).Result()
 	if err != nil {
-		log.Printf("Failed to get bookings for user %s: %v", userID, err)
+		slog.ErrorContext(ctx, "Failed to get bookings", "user_id", userID, "error", err)
 		return nil, err
 	}
 
@@ -72,7 +61,7 @@ func findUserBookings(userID string) ([]Booking, error) {
 		}
 		var booking Booking
 		if err := json.Unmarshal([]byte(bookingJSON.(string)), &booking); err != nil {
-			log.Printf("Failed to unmarshal booking data: %v", err)
+			slog.WarnContext(ctx, "Failed to unmarshal booking data", "error", err)
 			continue
 		}
 		bookings = append(bookings, booking)
@@ -81,7 +70,7 @@ func findUserBookings(userID string) ([]Booking, error) {
 	return bookings, nil
 }
 
-func findAllBookings() ([]Booking, error) {
+func findAllBookings(ctx context.Context) ([]Booking, error) {
 	keys, err := rdb.Keys(ctx, "booking:*").Result()
 	if err != nil {
 		return nil, err
@@ -93,7 +82,7 @@ func findAllBookings() ([]Booking, error) {
 
 	bookingsData, err := rdb.MGet(ctx, keys...).Result()
 	if err != nil {
-		log.Printf("Failed to get all bookings: %v", err)
+		slog.ErrorContext(ctx, "Failed to get all bookings", "error", err)
 		return nil, err
 	}
 
@@ -104,7 +93,7 @@ func findAllBookings() ([]Booking, error) {
 		}
 		var booking Booking
 		if err := json.Unmarshal([]byte(bookingJSON.(string)), &booking); err != nil {
-			log.Printf("Failed to unmarshal booking data: %v", err)
+			slog.WarnContext(ctx, "Failed to unmarshal booking data", "error", err)
 			continue
 		}
 		bookings = append(bookings, booking)
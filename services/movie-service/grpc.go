@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
+)
+
+// movieServer serves theaterpb.MovieService from the same store as the REST handlers
+type movieServer struct {
+	theaterpb.UnimplementedMovieServiceServer
+}
+
+func (m Movie) proto() *theaterpb.Movie {
+	return &theaterpb.Movie{Id: m.ID, Title: m.Title, Director: m.Director, Genre: m.Genre, Year: int32(m.Year)}
+}
+
+func (movieServer) ListMovies(ctx context.Context, req *theaterpb.ListMoviesRequest) (*theaterpb.ListMoviesResponse, error) {
+	var (
+		movies []Movie
+		err    error
+	)
+	if ids := req.GetIds(); len(ids) > 0 {
+		movies, err = findMoviesByIDs(ctx, ids)
+	} else {
+		movies, err = findAllMovies(ctx)
+	}
+	if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to retrieve movies")
+	}
+	resp := &theaterpb.ListMoviesResponse{Movies: make([]*theaterpb.Movie, 0, len(movies))}
+	for _, movie := range movies {
+		resp.Movies = append(resp.Movies, movie.proto())
+	}
+	return resp, nil
+}
+
+func (movieServer) GetMovie(ctx context.Context, req *theaterpb.GetMovieRequest) (*theaterpb.Movie, error) {
+	movie, err := findMovieByID(ctx, req.GetId())
+	if err == redis.Nil {
+		return nil, platform.GRPCError(ctx, codes.NotFound, codeMovieNotFound, "Movie not found")
+	} else if err != nil {
+		slog.ErrorContext(ctx, "Failed to get movie from Redis", "error", err)
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to get movie")
+	}
+	return movie.proto(), nil
+}
+
+func (movieServer) CreateMovie(ctx context.Context, req *theaterpb.CreateMovieRequest) (*theaterpb.Movie, error) {
+	movie := Movie{
+		Title:    req.GetTitle(),
+		Director: req.GetDirector(),
+		Genre:    req.GetGenre(),
+		Year:     int(req.GetYear()),
+	}
+	var invalid platform.InvalidError
+	if err := storeMovie(ctx, &movie); errors.As(err, &invalid) {
+		return nil, platform.GRPCError(ctx, codes.InvalidArgument, platform.CodeInvalidRequest, invalid.Error())
+	} else if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to save movie")
+	}
+	return movie.proto(), nil
+}
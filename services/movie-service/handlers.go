@@ -1,26 +1,26 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+
+	"msa-sample-01/pkg/platform"
 )
 
+// codeMovieNotFound is the problem code answered for an unknown movie ID
+const codeMovieNotFound = "MOVIE_NOT_FOUND"
+
 func moviesHandler(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/")
 	// Remove "movies/" prefix if present (from API Gateway routing)
 	path = strings.TrimPrefix(path, "movies/")
-	w.Header().Set("Content-Type", "application/json")
-	
-	// 실제 라우팅 정보를 헤더에 추가
-	w.Header().Set("X-Service-Cluster", getClusterName())
-	w.Header().Set("X-Pod-Name", os.Getenv("HOSTNAME"))
-	w.Header().Set("X-Service-Name", "movie-service")
 
 	switch r.Method {
 	case http.MethodGet:
@@ -33,72 +33,81 @@ func moviesHandler(w http.ResponseWriter, r *http.Request) {
 		if path == "" {
 			createMovieHandler(w, r)
 		} else {
-			http.Error(w, "Method not allowed on specific resource", http.StatusMethodNotAllowed)
+			platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed on specific resource")
 		}
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// metricsRoute maps a request path to a low-cardinality route label
+func metricsRoute(path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "/"), "movies/")
+	if path == "" {
+		return "/movies/"
 	}
+	return "/movies/{id}"
 }
 
 func createMovieHandler(w http.ResponseWriter, r *http.Request) {
 	var movie Movie
 	if err := json.NewDecoder(r.Body).Decode(&movie); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
-	movie.ID = uuid.New().String()
-
-	if err := saveMovie(movie); err != nil {
-		http.Error(w, "Failed to save movie", http.StatusInternalServerError)
+	var invalid platform.InvalidError
+	if err := storeMovie(r.Context(), &movie); errors.As(err, &invalid) {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, invalid.Error())
+		return
+	} else if err != nil {
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to save movie")
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(movie)
+	platform.WriteJSON(w, http.StatusCreated, movie)
+}
+
+// storeMovie validates the movie, assigns the ID and saves it; shared by REST and gRPC
+func storeMovie(ctx context.Context, movie *Movie) error {
+	if err := movie.validate(); err != nil {
+		return err
+	}
+	movie.ID = uuid.New().String()
+	return saveMovie(ctx, *movie)
 }
 
 func getMovieHandler(w http.ResponseWriter, r *http.Request, id string) {
-	movie, err := findMovieByID(id)
+	movie, err := findMovieByID(r.Context(), id)
 	if err == redis.Nil {
-		http.Error(w, "Movie not found", http.StatusNotFound)
+		platform.Error(w, r, http.StatusNotFound, codeMovieNotFound, "Movie not found")
 		return
 	} else if err != nil {
-		log.Printf("Failed to get movie from Redis: %v", err)
-		http.Error(w, "Failed to get movie", http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "Failed to get movie from Redis", "error", err)
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to get movie")
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(movie)
+	platform.WriteJSON(w, http.StatusOK, movie)
 }
 
 func getAllMoviesHandler(w http.ResponseWriter, r *http.Request) {
-	movies, err := findAllMovies()
-	if err != nil {
-		http.Error(w, "Failed to retrieve movies", http.StatusInternalServerError)
+	// ?ids=a,b,c 는 한 번의 MGET 으로 조회 (게이트웨이 GraphQL 의 N+1 방지)
+	if ids := r.URL.Query().Get("ids"); ids != "" {
+		movies, err := findMoviesByIDs(r.Context(), strings.Split(ids, ","))
+		if err != nil {
+			platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to retrieve movies")
+			return
+		}
+		platform.WriteJSON(w, http.StatusOK, movies)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(movies)
-}
-
-// getClusterName은 현재 파드가 실행 중인 클러스터를 판단합니다
-func getClusterName() string {
-	// 환경변수에서 클러스터명 확인
-	if cluster := os.Getenv("CLUSTER_NAME"); cluster != "" {
-		return cluster
-	}
-	
-	// 파드명에서 클러스터 정보 추출
-	hostname := os.Getenv("HOSTNAME")
-	if strings.Contains(hostname, "ctx1") {
-		return "ctx1"
-	} else if strings.Contains(hostname, "ctx2") {
-		return "ctx2"
+	movies, err := findAllMovies(r.Context())
+	if err != nil {
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to retrieve movies")
+		return
 	}
-	
-	// 기본값
-	return "unknown"
+
+	platform.WriteJSON(w, http.StatusOK, movies)
 }
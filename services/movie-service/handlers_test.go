@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
+)
+
+// useUnreachableRedis points the store at a closed port so valid requests fail at the store, not at validation
+func useUnreachableRedis(t *testing.T) {
+	t.Helper()
+	previous := rdb
+	rdb = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() {
+		rdb.Close()
+		rdb = previous
+	})
+}
+
+func TestCreateMovieValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		movie      Movie
+		wantStatus int
+		wantCode   string
+		wantGRPC   codes.Code
+	}{
+		{name: "valid", movie: Movie{Title: "Parasite", Year: 2019}, wantStatus: http.StatusInternalServerError, wantCode: platform.CodeStoreError, wantGRPC: codes.Internal},
+		{name: "year not set", movie: Movie{Title: "Parasite"}, wantStatus: http.StatusInternalServerError, wantCode: platform.CodeStoreError, wantGRPC: codes.Internal},
+		{name: "missing title", movie: Movie{Director: "Bong Joon-ho"}, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+		{name: "year before 1888", movie: Movie{Title: "Parasite", Year: 1800}, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useUnreachableRedis(t)
+
+			body, _ := json.Marshal(tt.movie)
+			w := httptest.NewRecorder()
+			moviesHandler(w, httptest.NewRequest(http.MethodPost, "/movies/", strings.NewReader(string(body))))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("REST status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var problem platform.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("REST code = %q, want %q", problem.Code, tt.wantCode)
+			}
+
+			// gRPC 도 같은 검증 결과
+			_, err := movieServer{}.CreateMovie(context.Background(), &theaterpb.CreateMovieRequest{Title: tt.movie.Title, Director: tt.movie.Director, Year: int32(tt.movie.Year)})
+			if got := status.Code(err); got != tt.wantGRPC {
+				t.Errorf("gRPC code = %v, want %v", got, tt.wantGRPC)
+			}
+		})
+	}
+}
+
+func TestMoviesHandlerInvalidBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	moviesHandler(w, httptest.NewRequest(http.MethodPost, "/movies/", strings.NewReader(`{"title":`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	moviesHandler(w, httptest.NewRequest(http.MethodPost, "/movies/1", strings.NewReader(`{"title":"Parasite"}`)))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST on a movie: status = %d, want 405", w.Code)
+	}
+}
@@ -1,15 +1,34 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	_ "embed"
+	"log/slog"
+	"os"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
 )
 
+const serviceName = "movie-service"
+
+// openapiSpec is served at /openapi.json and validates requests when OPENAPI_VALIDATION is set
+//
+//go:embed openapi.yaml
+var openapiSpec []byte
+
 func main() {
-	http.HandleFunc("/", moviesHandler)
+	// 로깅, 설정, 트레이싱, 메트릭, Redis, 프로브, gRPC 서버, 정상 종료는 platform 모듈이 담당
+	svc, err := platform.New(platform.Options{Name: serviceName, Port: 8082, GRPCPort: 9082, Route: metricsRoute, OpenAPI: openapiSpec})
+	if err != nil {
+		slog.Error("Invalid service config", "error", err)
+		os.Exit(1)
+	}
+	rdb = svc.Redis
+	svc.Handle(moviesHandler)
+	theaterpb.RegisterMovieServiceServer(svc.GRPC, movieServer{})
 
-	log.Println("Movie Service started on :8082")
-	if err := http.ListenAndServe(":8082", nil); err != nil {
-		log.Fatalf("Could not start server: %s\n", err)
+	if err := svc.Run(); err != nil {
+		slog.Error("Could not start server", "error", err)
+		os.Exit(1)
 	}
 }
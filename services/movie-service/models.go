@@ -1,9 +1,26 @@
 package main
 
+import "msa-sample-01/pkg/platform"
+
+// minMovieYear matches the minimum of year in openapi.yaml; 0 means the year is not set
+const minMovieYear = 1888
+
 // Movie represents a movie model
 type Movie struct {
 	ID       string `json:"id"`
 	Title    string `json:"title"`
 	Director string `json:"director"`
 	Genre    string `json:"genre"`
+	Year     int    `json:"year,omitempty"` // UI 와 초기 데이터(redis.yaml)가 사용
+}
+
+// validate checks the constraints of openapi.yaml (title required, year >= 1888), for REST and gRPC alike
+func (m Movie) validate() error {
+	if m.Title == "" {
+		return platform.InvalidError("title is required")
+	}
+	if m.Year != 0 && m.Year < minMovieYear {
+		return platform.InvalidError("year must be 1888 or later")
+	}
+	return nil
 }
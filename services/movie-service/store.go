@@ -3,39 +3,28 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 
 	"github.com/go-redis/redis/v8"
 )
 
-var (
-	rdb *redis.Client
-	ctx = context.Background()
-)
-
-func init() {
-	// Initialize Redis client
-	rdb = redis.NewClient(&redis.Options{
-		Addr:     "redis:6379",       // Redis service from docker-compose
-		Password: "",               // No password set
-		DB:       0,                // Default DB
-	})
-}
+// rdb is the platform Redis client, set in main; every call takes the request context so Redis spans join the request trace
+var rdb *redis.Client
 
-func saveMovie(movie Movie) error {
+func saveMovie(ctx context.Context, movie Movie) error {
 	movieJSON, err := json.Marshal(movie)
 	if err != nil {
 		return err
 	}
 
 	if err := rdb.Set(ctx, "movie:"+movie.ID, movieJSON, 0).Err(); err != nil {
-		log.Printf("Failed to save movie to Redis: %v", err)
+		slog.ErrorContext(ctx, "Failed to save movie to Redis", "error", err)
 		return err
 	}
 	return nil
 }
 
-func findMovieByID(id string) (*Movie, error) {
+func findMovieByID(ctx context.Context, id string) (*Movie, error) {
 	movieJSON, err := rdb.Get(ctx, "movie:"+id).Result()
 	if err != nil {
 		return nil, err
@@ -48,10 +37,10 @@ func findMovieByID(id string) (*Movie, error) {
 	return &movie, nil
 }
 
-func findAllMovies() ([]Movie, error) {
+func findAllMovies(ctx context.Context) ([]Movie, error) {
 	keys, err := rdb.Keys(ctx, "movie:*").Result()
 	if err != nil {
-		log.Printf("Failed to get movie keys from Redis: %v", err)
+		slog.ErrorContext(ctx, "Failed to get movie keys from Redis", "error", err)
 		return nil, err
 	}
 
@@ -59,9 +48,23 @@ func findAllMovies() ([]Movie, error) {
 		return []Movie{}, nil
 	}
 
+	return getMovies(ctx, keys)
+}
+
+// findMoviesByIDs returns the movies that exist among ids, in the order of ids
+func findMoviesByIDs(ctx context.Context, ids []string) ([]Movie, error) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, "movie:"+id)
+	}
+	return getMovies(ctx, keys)
+}
+
+// getMovies reads the keys with one MGET, skipping missing and undecodable entries
+func getMovies(ctx context.Context, keys []string) ([]Movie, error) {
 	moviesData, err := rdb.MGet(ctx, keys...).Result()
 	if err != nil {
-		log.Printf("Failed to get movies from Redis: %v", err)
+		slog.ErrorContext(ctx, "Failed to get movies from Redis", "error", err)
 		return nil, err
 	}
 
@@ -72,7 +75,7 @@ func findAllMovies() ([]Movie, error) {
 		}
 		var movie Movie
 		if err := json.Unmarshal([]byte(movieJSON.(string)), &movie); err != nil {
-			log.Printf("Failed to unmarshal movie data: %v", err)
+			slog.WarnContext(ctx, "Failed to unmarshal movie data", "error", err)
 			continue
 		}
 		movies = append(movies, movie)
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
+)
+
+// userServer serves theaterpb.UserService from the same store as the REST handlers
+type userServer struct {
+	theaterpb.UnimplementedUserServiceServer
+}
+
+func (u User) proto() *theaterpb.User {
+	return &theaterpb.User{Id: u.ID, Name: u.Name, Email: u.Email}
+}
+
+func (userServer) ListUsers(ctx context.Context, _ *theaterpb.ListUsersRequest) (*theaterpb.ListUsersResponse, error) {
+	users, err := getAllUsers(ctx)
+	if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to get users")
+	}
+	resp := &theaterpb.ListUsersResponse{Users: make([]*theaterpb.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, user.proto())
+	}
+	return resp, nil
+}
+
+func (userServer) GetUser(ctx context.Context, req *theaterpb.GetUserRequest) (*theaterpb.User, error) {
+	user, err := findUserByID(ctx, req.GetId())
+	if err == redis.Nil {
+		return nil, platform.GRPCError(ctx, codes.NotFound, codeUserNotFound, "User not found")
+	} else if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to get user")
+	}
+	return user.proto(), nil
+}
+
+func (userServer) CreateUser(ctx context.Context, req *theaterpb.CreateUserRequest) (*theaterpb.User, error) {
+	user := User{Name: req.GetName(), Email: req.GetEmail()}
+	var invalid platform.InvalidError
+	if err := storeUser(ctx, &user); errors.As(err, &invalid) {
+		return nil, platform.GRPCError(ctx, codes.InvalidArgument, platform.CodeInvalidRequest, invalid.Error())
+	} else if err != nil {
+		return nil, platform.GRPCError(ctx, codes.Internal, platform.CodeStoreError, "Failed to save user")
+	}
+	return user.proto(), nil
+}
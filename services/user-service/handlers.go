@@ -1,32 +1,32 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+
+	"msa-sample-01/pkg/platform"
 )
 
+// codeUserNotFound is the problem code answered for an unknown user ID
+const codeUserNotFound = "USER_NOT_FOUND"
+
 func usersHandler(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/")
 	// Remove "users/" prefix if present (from API Gateway routing)
 	path = strings.TrimPrefix(path, "users/")
-	w.Header().Set("Content-Type", "application/json")
-	
-	// 실제 라우팅 정보를 헤더에 추가
-	w.Header().Set("X-Service-Cluster", getClusterName())
-	w.Header().Set("X-Pod-Name", os.Getenv("HOSTNAME"))
-	w.Header().Set("X-Service-Name", "user-service")
-	
+
 	switch r.Method {
 	case http.MethodPost:
 		if path == "" {
 			createUserHandler(w, r)
 		} else {
-			http.Error(w, "Invalid path for POST", http.StatusBadRequest)
+			platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid path for POST")
 		}
 	case http.MethodGet:
 		if path == "" {
@@ -35,65 +35,65 @@ func usersHandler(w http.ResponseWriter, r *http.Request) {
 			getUserHandler(w, r, path)
 		}
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		platform.Error(w, r, http.StatusMethodNotAllowed, platform.CodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
+// metricsRoute maps a request path to a low-cardinality route label
+func metricsRoute(path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "/"), "users/")
+	if path == "" {
+		return "/users/"
+	}
+	return "/users/{id}"
+}
+
 func createUserHandler(w http.ResponseWriter, r *http.Request) {
 	var user User
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
-	user.ID = uuid.New().String()
-
-	if err := saveUser(user); err != nil {
-		http.Error(w, "Failed to save user", http.StatusInternalServerError)
+	var invalid platform.InvalidError
+	if err := storeUser(r.Context(), &user); errors.As(err, &invalid) {
+		platform.Error(w, r, http.StatusBadRequest, platform.CodeInvalidRequest, invalid.Error())
+		return
+	} else if err != nil {
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to save user")
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(user)
+	platform.WriteJSON(w, http.StatusCreated, user)
+}
+
+// storeUser validates the user, assigns the ID and saves it; shared by REST and gRPC
+func storeUser(ctx context.Context, user *User) error {
+	if err := user.validate(); err != nil {
+		return err
+	}
+	user.ID = uuid.New().String()
+	return saveUser(ctx, *user)
 }
 
 func getAllUsersHandler(w http.ResponseWriter, r *http.Request) {
-	users, err := getAllUsers()
+	users, err := getAllUsers(r.Context())
 	if err != nil {
-		http.Error(w, "Failed to get users", http.StatusInternalServerError)
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to get users")
 		return
 	}
-	json.NewEncoder(w).Encode(users)
+	platform.WriteJSON(w, http.StatusOK, users)
 }
 
 func getUserHandler(w http.ResponseWriter, r *http.Request, userID string) {
-	user, err := findUserByID(userID)
+	user, err := findUserByID(r.Context(), userID)
 	if err == redis.Nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		platform.Error(w, r, http.StatusNotFound, codeUserNotFound, "User not found")
 		return
 	} else if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		platform.Error(w, r, http.StatusInternalServerError, platform.CodeStoreError, "Failed to get user")
 		return
 	}
 
-	json.NewEncoder(w).Encode(user)
-}
-
-// getClusterName은 현재 파드가 실행 중인 클러스터를 판단합니다
-func getClusterName() string {
-	// 환경변수에서 클러스터명 확인
-	if cluster := os.Getenv("CLUSTER_NAME"); cluster != "" {
-		return cluster
-	}
-	
-	// 파드명에서 클러스터 정보 추출
-	hostname := os.Getenv("HOSTNAME")
-	if strings.Contains(hostname, "ctx1") {
-		return "ctx1"
-	} else if strings.Contains(hostname, "ctx2") {
-		return "ctx2"
-	}
-	
-	// 기본값
-	return "unknown"
+	platform.WriteJSON(w, http.StatusOK, user)
 }
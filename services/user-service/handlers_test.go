@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
+)
+
+// useUnreachableRedis points the store at a closed port so valid requests fail at the store, not at validation
+func useUnreachableRedis(t *testing.T) {
+	t.Helper()
+	previous := rdb
+	rdb = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() {
+		rdb.Close()
+		rdb = previous
+	})
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		user       User
+		wantStatus int
+		wantCode   string
+		wantGRPC   codes.Code
+	}{
+		{name: "valid", user: User{Name: "Kim", Email: "kim@example.com"}, wantStatus: http.StatusInternalServerError, wantCode: platform.CodeStoreError, wantGRPC: codes.Internal},
+		{name: "missing name", user: User{Email: "kim@example.com"}, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+		{name: "missing email", user: User{Name: "Kim"}, wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+		{name: "empty", wantStatus: http.StatusBadRequest, wantCode: platform.CodeInvalidRequest, wantGRPC: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useUnreachableRedis(t)
+
+			body, _ := json.Marshal(tt.user)
+			w := httptest.NewRecorder()
+			usersHandler(w, httptest.NewRequest(http.MethodPost, "/users/", strings.NewReader(string(body))))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("REST status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var problem platform.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("REST code = %q, want %q", problem.Code, tt.wantCode)
+			}
+
+			// gRPC 도 같은 검증 결과
+			_, err := userServer{}.CreateUser(context.Background(), &theaterpb.CreateUserRequest{Name: tt.user.Name, Email: tt.user.Email})
+			if got := status.Code(err); got != tt.wantGRPC {
+				t.Errorf("gRPC code = %v, want %v", got, tt.wantGRPC)
+			}
+		})
+	}
+}
+
+func TestUsersHandlerInvalidBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	usersHandler(w, httptest.NewRequest(http.MethodPost, "/users/", strings.NewReader(`{"name":`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	usersHandler(w, httptest.NewRequest(http.MethodDelete, "/users/1", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE status = %d, want 405", w.Code)
+	}
+}
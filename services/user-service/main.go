@@ -1,15 +1,34 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	_ "embed"
+	"log/slog"
+	"os"
+
+	"msa-sample-01/pkg/platform"
+	"msa-sample-01/pkg/theaterpb"
 )
 
+const serviceName = "user-service"
+
+// openapiSpec is served at /openapi.json and validates requests when OPENAPI_VALIDATION is set
+//
+//go:embed openapi.yaml
+var openapiSpec []byte
+
 func main() {
-	http.HandleFunc("/", usersHandler)
+	// 로깅, 설정, 트레이싱, 메트릭, Redis, 프로브, gRPC 서버, 정상 종료는 platform 모듈이 담당
+	svc, err := platform.New(platform.Options{Name: serviceName, Port: 8081, GRPCPort: 9081, Route: metricsRoute, OpenAPI: openapiSpec})
+	if err != nil {
+		slog.Error("Invalid service config", "error", err)
+		os.Exit(1)
+	}
+	rdb = svc.Redis
+	svc.Handle(usersHandler)
+	theaterpb.RegisterUserServiceServer(svc.GRPC, userServer{})
 
-	log.Println("User Service started on :8081")
-	if err := http.ListenAndServe(":8081", nil); err != nil {
-		log.Fatalf("Could not start server: %s\n", err)
+	if err := svc.Run(); err != nil {
+		slog.Error("Could not start server", "error", err)
+		os.Exit(1)
 	}
 }
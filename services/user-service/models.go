@@ -1,8 +1,18 @@
 package main
 
+import "msa-sample-01/pkg/platform"
+
 // User represents a user model
 type User struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
+
+// validate checks the fields openapi.yaml marks as required, for REST and gRPC alike
+func (u User) validate() error {
+	if u.Name == "" || u.Email == "" {
+		return platform.InvalidError("name and email are required")
+	}
+	return nil
+}
@@ -3,39 +3,28 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 
 	"github.com/go-redis/redis/v8"
 )
 
-var (
-	rdb *redis.Client
-	ctx = context.Background()
-)
-
-func init() {
-	// Initialize Redis client
-	rdb = redis.NewClient(&redis.Options{
-		Addr:     "redis:6379",       // Redis service from docker-compose
-		Password: "",               // No password set
-		DB:       0,                // Default DB
-	})
-}
+// rdb is the platform Redis client, set in main; every call takes the request context so Redis spans join the request trace
+var rdb *redis.Client
 
-func saveUser(user User) error {
+func saveUser(ctx context.Context, user User) error {
 	userJSON, err := json.Marshal(user)
 	if err != nil {
 		return err
 	}
 
 	if err := rdb.Set(ctx, "user:"+user.ID, userJSON, 0).Err(); err != nil {
-		log.Printf("Failed to save user to Redis: %v", err)
+		slog.ErrorContext(ctx, "Failed to save user to Redis", "error", err)
 		return err
 	}
 	return nil
 }
 
-func findUserByID(id string) (*User, error) {
+func findUserByID(ctx context.Context, id string) (*User, error) {
 	userJSON, err := rdb.Get(ctx, "user:"+id).Result()
 	if err != nil {
 		return nil, err
@@ -48,23 +37,23 @@ func findUserByID(id string) (*User, error) {
 	return &user, nil
 }
 
-func getAllUsers() ([]User, error) {
+func getAllUsers(ctx context.Context) ([]User, error) {
 	keys, err := rdb.Keys(ctx, "user:*").Result()
 	if err != nil {
 		return nil, err
 	}
 
-	var users []User
+	users := []User{} // 사용자가 없어도 null 이 아닌 [] 응답
 	for _, key := range keys {
 		userJSON, err := rdb.Get(ctx, key).Result()
 		if err != nil {
-			log.Printf("Failed to get user for key %s: %v", key, err)
+			slog.WarnContext(ctx, "Failed to get user", "key", key, "error", err)
 			continue
 		}
 
 		var user User
 		if err := json.Unmarshal([]byte(userJSON), &user); err != nil {
-			log.Printf("Failed to unmarshal user for key %s: %v", key, err)
+			slog.WarnContext(ctx, "Failed to unmarshal user", "key", key, "error", err)
 			continue
 		}
 		users = append(users, user)